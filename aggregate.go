@@ -0,0 +1,374 @@
+package gotable
+
+import (
+	"math"
+	"sort"
+)
+
+// AggFunc computes one value from a list of cells -- their average, their
+// maximum, and so on. The list may contain empty cells; unless stated
+// otherwise the aggregates below ignore them. An aggregate with nothing to
+// work on returns an empty Cell.
+type AggFunc func(c []Cell) Cell
+
+// cellFloat returns the numeric value of c as a float64. The second return
+// value is false if c does not hold a number.
+func cellFloat(c *Cell) (float64, bool) {
+	switch c.Type {
+	case CELLINT:
+		return float64(c.Ival), true
+	case CELLFLOAT:
+		return c.Fval, true
+	case CELLDECIMAL:
+		return c.Mval.Float64(), true
+	}
+	return 0, false
+}
+
+// numbers returns the numeric values found in c, in order
+func numbers(c []Cell) []float64 {
+	var a []float64
+	for i := 0; i < len(c); i++ {
+		if f, ok := cellFloat(&c[i]); ok {
+			a = append(a, f)
+		}
+	}
+	return a
+}
+
+// AggSum returns the sum of the numeric cells. The result is a CELLINT if all
+// the numbers are ints, a CELLFLOAT if any of them is a float, and otherwise
+// an exact CELLDECIMAL. A decimal sum that does not fit in a Decimal is
+// returned as a CELLFLOAT.
+func AggSum(c []Cell) Cell {
+	var r Cell
+	var isum int64
+	var fsum float64
+	var msum Cell
+	for i := 0; i < len(c); i++ {
+		switch c[i].Type {
+		case CELLINT:
+			isum += c[i].Ival
+			if r.Type == 0 {
+				r.Type = CELLINT
+			}
+		case CELLFLOAT:
+			fsum += c[i].Fval
+			r.Type = CELLFLOAT
+		case CELLDECIMAL:
+			msum = addDecimal(msum, c[i].Mval)
+			if r.Type != CELLFLOAT {
+				r.Type = CELLDECIMAL
+			}
+		}
+	}
+	switch r.Type {
+	case CELLINT:
+		r.Ival = isum
+	case CELLFLOAT:
+		r.Fval = fsum + float64(isum) + msum.Fval + msum.Mval.Float64()
+	case CELLDECIMAL:
+		if msum = addDecimal(msum, Decimal{Units: isum}); msum.Type == CELLFLOAT {
+			r = Cell{Type: CELLFLOAT, Fval: msum.Fval}
+		} else {
+			r.Mval = msum.Mval
+		}
+	}
+	return r
+}
+
+// AggAvg returns the average of the numeric cells as a CELLFLOAT
+func AggAvg(c []Cell) Cell {
+	var r Cell
+	a := numbers(c)
+	if len(a) == 0 {
+		return r
+	}
+	var sum float64
+	for i := 0; i < len(a); i++ {
+		sum += a[i]
+	}
+	r.Type = CELLFLOAT
+	r.Fval = sum / float64(len(a))
+	return r
+}
+
+// aggExtreme returns the non-empty cell that sorts first when dir is 1, or
+// last when dir is -1
+func aggExtreme(c []Cell, dir int) Cell {
+	var r Cell
+	for i := 0; i < len(c); i++ {
+		if isNullCell(&c[i]) {
+			continue
+		}
+		if r.Type == 0 || dir*compareCells(&c[i], &r) < 0 {
+			r = c[i]
+		}
+	}
+	return r
+}
+
+// AggMin returns the smallest non-empty cell. It works with numbers, strings,
+// dates and datetimes.
+func AggMin(c []Cell) Cell {
+	return aggExtreme(c, 1)
+}
+
+// AggMax returns the largest non-empty cell. It works with numbers, strings,
+// dates and datetimes.
+func AggMax(c []Cell) Cell {
+	return aggExtreme(c, -1)
+}
+
+// AggCount returns the number of non-empty cells as a CELLINT
+func AggCount(c []Cell) Cell {
+	var n int64
+	for i := 0; i < len(c); i++ {
+		if !isNullCell(&c[i]) {
+			n++
+		}
+	}
+	return Cell{Type: CELLINT, Ival: n}
+}
+
+// AggCountNull returns the number of null cells as a CELLINT
+func AggCountNull(c []Cell) Cell {
+	var n int64
+	for i := 0; i < len(c); i++ {
+		if isNullCell(&c[i]) {
+			n++
+		}
+	}
+	return Cell{Type: CELLINT, Ival: n}
+}
+
+// AggCountAll returns the number of cells, null or not, as a CELLINT
+func AggCountAll(c []Cell) Cell {
+	return Cell{Type: CELLINT, Ival: int64(len(c))}
+}
+
+// NullsAsZero returns an aggregate that works like f except that null cells
+// count as 0 instead of being skipped. For example, NullsAsZero(AggAvg)
+// includes the null cells when it divides the sum.
+func NullsAsZero(f AggFunc) AggFunc {
+	return func(c []Cell) Cell {
+		a := make([]Cell, len(c))
+		for i := 0; i < len(c); i++ {
+			a[i] = c[i]
+			if isNullCell(&a[i]) {
+				a[i] = Cell{Type: CELLINT}
+			}
+		}
+		return f(a)
+	}
+}
+
+// cellKey is used to find equal cell values
+type cellKey struct {
+	Type int
+	Ival int64
+	Fval float64
+	Sval string
+	Dval int64
+}
+
+// keyOf returns the cellKey for c. Cells with equal values have equal keys.
+func keyOf(c *Cell) cellKey {
+	k := cellKey{Type: c.Type}
+	switch c.Type {
+	case CELLINT:
+		k.Ival = c.Ival
+	case CELLFLOAT:
+		k.Fval = c.Fval
+	case CELLSTRING:
+		k.Sval = c.Sval
+	case CELLDECIMAL:
+		k.Sval = c.Mval.normalized().String()
+	case CELLDATE, CELLDATETIME:
+		k.Dval = c.Dval.UnixNano()
+	}
+	return k
+}
+
+// AggCountDistinct returns the number of different values among the non-empty
+// cells as a CELLINT
+func AggCountDistinct(c []Cell) Cell {
+	m := map[cellKey]bool{}
+	for i := 0; i < len(c); i++ {
+		if isNullCell(&c[i]) {
+			continue
+		}
+		m[keyOf(&c[i])] = true
+	}
+	return Cell{Type: CELLINT, Ival: int64(len(m))}
+}
+
+// AggMedian returns the median of the numeric cells as a CELLFLOAT
+func AggMedian(c []Cell) Cell {
+	return AggPercentile(50)(c)
+}
+
+// AggPercentile returns an AggFunc that computes the p-th percentile (0 - 100)
+// of the numeric cells as a CELLFLOAT. Values between two cells are linearly
+// interpolated.
+func AggPercentile(p float64) AggFunc {
+	if p < 0 {
+		p = 0
+	}
+	if p > 100 {
+		p = 100
+	}
+	return func(c []Cell) Cell {
+		var r Cell
+		a := numbers(c)
+		if len(a) == 0 {
+			return r
+		}
+		sort.Float64s(a)
+		x := p / 100 * float64(len(a)-1)
+		i := int(math.Floor(x))
+		r.Type = CELLFLOAT
+		r.Fval = a[i]
+		if i+1 < len(a) {
+			r.Fval += (x - float64(i)) * (a[i+1] - a[i])
+		}
+		return r
+	}
+}
+
+// stdDev returns the standard deviation of the numeric cells, dividing by
+// n - d. That is, d = 1 for a sample and 0 for the whole population.
+func stdDev(c []Cell, d int) Cell {
+	var r Cell
+	a := numbers(c)
+	if len(a)-d < 1 {
+		return r
+	}
+	var mean float64
+	for i := 0; i < len(a); i++ {
+		mean += a[i]
+	}
+	mean /= float64(len(a))
+	var ss float64
+	for i := 0; i < len(a); i++ {
+		ss += (a[i] - mean) * (a[i] - mean)
+	}
+	r.Type = CELLFLOAT
+	r.Fval = math.Sqrt(ss / float64(len(a)-d))
+	return r
+}
+
+// AggStdDev returns the sample standard deviation of the numeric cells as a
+// CELLFLOAT. It needs at least two numbers.
+func AggStdDev(c []Cell) Cell {
+	return stdDev(c, 1)
+}
+
+// AggStdDevP returns the population standard deviation of the numeric cells
+// as a CELLFLOAT
+func AggStdDevP(c []Cell) Cell {
+	return stdDev(c, 0)
+}
+
+// colCells returns the cells of column col in the supplied rows. Rows outside
+// the table are skipped.
+func (t *Table) colCells(col int, rows []int) []Cell {
+	var c []Cell
+	if col < 0 || col >= len(t.ColDefs) {
+		return c
+	}
+	for i := 0; i < len(rows); i++ {
+		if rows[i] >= 0 && rows[i] < len(t.Row) {
+			c = append(c, t.Row[rows[i]].Col[col])
+		}
+	}
+	return c
+}
+
+// rowRange returns the row indeces from,to limited to the rows of the table
+func (t *Table) rowRange(from, to int) []int {
+	var r []int
+	if from < 0 {
+		from = 0
+	}
+	if to >= len(t.Row) {
+		to = len(t.Row) - 1
+	}
+	for i := from; i <= to; i++ {
+		r = append(r, i)
+	}
+	return r
+}
+
+// Aggregate applies f to all the cells of column col. It returns a Cell
+func (t *Table) Aggregate(col int, f AggFunc) Cell {
+	return t.AggregateRows(col, 0, len(t.Row)-1, f)
+}
+
+// AggregateRows applies f to the cells of column col in rows from thru to. It returns a Cell
+func (t *Table) AggregateRows(col, from, to int, f AggFunc) Cell {
+	return f(t.colCells(col, t.rowRange(from, to)))
+}
+
+// AggregateRowset applies f to the cells of column col in the rows of rowset rsid. It returns a Cell
+func (t *Table) AggregateRowset(rsid, col int, f AggFunc) Cell {
+	return f(t.colCells(col, t.GetRowset(rsid)))
+}
+
+// Avg computes the average of the numbers in column col. It returns a Cell
+func (t *Table) Avg(col int) Cell {
+	return t.Aggregate(col, AggAvg)
+}
+
+// Min returns the smallest value in column col. It returns a Cell
+func (t *Table) Min(col int) Cell {
+	return t.Aggregate(col, AggMin)
+}
+
+// Max returns the largest value in column col. It returns a Cell
+func (t *Table) Max(col int) Cell {
+	return t.Aggregate(col, AggMax)
+}
+
+// Count returns the number of non-empty cells in column col. It returns a Cell
+func (t *Table) Count(col int) Cell {
+	return t.Aggregate(col, AggCount)
+}
+
+// CountDistinct returns the number of different values in column col. It returns a Cell
+func (t *Table) CountDistinct(col int) Cell {
+	return t.Aggregate(col, AggCountDistinct)
+}
+
+// Median computes the median of the numbers in column col. It returns a Cell
+func (t *Table) Median(col int) Cell {
+	return t.Aggregate(col, AggMedian)
+}
+
+// Percentile computes the p-th percentile (0 - 100) of the numbers in column col. It returns a Cell
+func (t *Table) Percentile(col int, p float64) Cell {
+	return t.Aggregate(col, AggPercentile(p))
+}
+
+// StdDev computes the sample standard deviation of the numbers in column col. It returns a Cell
+func (t *Table) StdDev(col int) Cell {
+	return t.Aggregate(col, AggStdDev)
+}
+
+// InsertAggregateRow computes an aggregate for each column in aggs over the Row range: from,to.
+// It then inserts a new Row at index row and sets the values at those columns to the results.
+func (t *Table) InsertAggregateRow(row, from, to int, aggs map[int]AggFunc) {
+	vals := map[int]Cell{}
+	for col, f := range aggs {
+		if col >= 0 && col < len(t.ColDefs) {
+			vals[col] = t.AggregateRows(col, from, to, f)
+		}
+	}
+	if row < 0 || row >= len(t.Row) {
+		row = len(t.Row) // InsertRow appends the row
+	}
+	t.InsertRow(row)
+	for col, c := range vals {
+		t.Put(row, col, c)
+	}
+}
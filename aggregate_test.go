@@ -0,0 +1,77 @@
+package gotable
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestAggregates(t *testing.T) {
+	var tbl Table
+	tbl.Init()
+	tbl.AddColumn("Name", 10, CELLSTRING, COLJUSTIFYLEFT)
+	tbl.AddColumn("Qty", 5, CELLINT, COLJUSTIFYRIGHT)
+	tbl.AddColumn("Price", 10, CELLFLOAT, COLJUSTIFYRIGHT)
+	tbl.AddColumn("Date", 10, CELLDATE, COLJUSTIFYLEFT)
+
+	d := time.Date(2017, time.May, 1, 0, 0, 0, 0, time.UTC)
+	var data = []struct {
+		name  string
+		qty   int64
+		price float64
+	}{
+		{"apple", 4, 1.5}, {"pear", 2, 2.0}, {"apple", 8, 0.5}, {"plum", 6, 3.0},
+	}
+	for i := 0; i < len(data); i++ {
+		tbl.AddRow()
+		tbl.Puts(-1, 0, data[i].name)
+		tbl.Puti(-1, 1, data[i].qty)
+		tbl.Putf(-1, 2, data[i].price)
+		tbl.Putd(-1, 3, d.AddDate(0, 0, i))
+	}
+	tbl.AddRow() // a row of empty cells, ignored by all aggregates
+
+	checkf := func(name string, c Cell, expect float64) {
+		if c.Type != CELLFLOAT || math.Abs(c.Fval-expect) > 0.000001 {
+			t.Errorf("aggregate_test: %s: expected %f, found %#v\n", name, expect, c)
+		}
+	}
+	checki := func(name string, c Cell, expect int64) {
+		if c.Type != CELLINT || c.Ival != expect {
+			t.Errorf("aggregate_test: %s: expected %d, found %#v\n", name, expect, c)
+		}
+	}
+
+	checkf("Avg", tbl.Avg(1), 5)
+	checkf("Median", tbl.Median(1), 5)
+	checkf("Percentile", tbl.Percentile(1, 25), 3.5)
+	checkf("StdDev", tbl.StdDev(1), math.Sqrt(20.0/3.0))
+	checkf("StdDevP", tbl.Aggregate(1, AggStdDevP), math.Sqrt(5))
+	checki("Count", tbl.Count(0), 4)
+	checki("CountDistinct", tbl.CountDistinct(0), 3)
+	checki("Min", tbl.Min(1), 2)
+	checki("Max", tbl.Max(1), 8)
+	checki("AggregateRows", tbl.AggregateRows(1, 1, 2, AggSum), 10)
+	checkf("AggSum", tbl.Aggregate(2, AggSum), 7)
+
+	if c := tbl.Max(3); c.Type != CELLDATE || !c.Dval.Equal(d.AddDate(0, 0, 3)) {
+		t.Errorf("aggregate_test: Max date: found %#v\n", c)
+	}
+	if c := tbl.Min(0); c.Sval != "apple" {
+		t.Errorf("aggregate_test: Min string: found %#v\n", c)
+	}
+	if c := tbl.StdDev(3); c.Type != 0 {
+		t.Errorf("aggregate_test: Expected empty cell for StdDev of dates, found %#v\n", c)
+	}
+
+	rsid := tbl.CreateRowset()
+	tbl.AppendToRowset(rsid, 0)
+	tbl.AppendToRowset(rsid, 2)
+	checkf("AggregateRowset", tbl.AggregateRowset(rsid, 2, AggAvg), 1)
+
+	tbl.InsertAggregateRow(-1, 0, tbl.RowCount()-1, map[int]AggFunc{1: AggMax, 2: AggAvg, 3: AggMin})
+	n := tbl.RowCount() - 1
+	if tbl.Geti(n, 1) != 8 || tbl.Getf(n, 2) != 1.75 || !tbl.Getd(n, 3).Equal(d) {
+		t.Errorf("aggregate_test: InsertAggregateRow: found %#v\n", tbl.Row[n].Col)
+	}
+}
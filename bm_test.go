@@ -209,3 +209,31 @@ func BenchmarkTablePDF(b *testing.B) {
 		})
 	}
 }
+
+// BenchMark of a two key sort
+func BenchmarkTableSort(b *testing.B) {
+	benchmarks := []struct {
+		name string
+		rows int
+	}{
+		{"Table-Sort-Rows-100", 100},
+		{"Table-Sort-Rows-1000", 1000},
+		{"Table-Sort-Rows-10000", 10000},
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+
+			for n := 0; n < b.N; n++ {
+				b.StopTimer()
+				tbl := getTable(bm.rows)
+				for i := 0; i < bm.rows; i++ {
+					tbl.Puti(i, 1, int64((i*7919)%bm.rows))
+				}
+				b.StartTimer()
+				tbl.SortBy(0, tbl.RowCount()-1, SortKey{Col: 1}, SortKey{Col: 0, Desc: true})
+			}
+
+		})
+	}
+}
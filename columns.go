@@ -0,0 +1,157 @@
+package gotable
+
+import (
+	"strconv"
+	"strings"
+)
+
+// InsertColumn adds a new ColumnDef to the table at index idx, shifting the
+// columns at idx and beyond one place to the right. Every row gets an empty
+// cell in the new column. If idx equals the number of columns the column is
+// appended.
+func (t *Table) InsertColumn(idx int, title string, width, celltype int, justification int) error {
+	if idx != t.ColCount() {
+		if err := t.HasValidColumn(idx); err != nil {
+			return err
+		}
+	}
+	var cd = ColumnDef{
+		ColTitle: title, Width: width,
+		CellType: celltype, Justify: justification,
+		Fdecimals: 2, HTMLWidth: -1,
+	}
+	t.AdjustColumnHeader(&cd)
+	t.AdjustFormatString(&cd)
+
+	var cds []ColumnDef
+	var order []int
+	for i := 0; i < len(t.ColDefs); i++ {
+		if i == idx {
+			cds = append(cds, cd)
+			order = append(order, -1)
+		}
+		cds = append(cds, t.ColDefs[i])
+		order = append(order, i)
+	}
+	if idx == len(t.ColDefs) {
+		cds = append(cds, cd)
+		order = append(order, -1)
+	}
+	t.setColumns(cds, order)
+	return nil
+}
+
+// DeleteColumn removes the column at index idx along with its cells, css and
+// format rules.
+func (t *Table) DeleteColumn(idx int) error {
+	if err := t.HasValidColumn(idx); err != nil {
+		return err
+	}
+	var cds []ColumnDef
+	var order []int
+	for i := 0; i < len(t.ColDefs); i++ {
+		if i != idx {
+			cds = append(cds, t.ColDefs[i])
+			order = append(order, i)
+		}
+	}
+	t.setColumns(cds, order)
+	return nil
+}
+
+// MoveColumn moves the column at index from so that it ends up at index to.
+// Its cells, css and format rules move with it.
+func (t *Table) MoveColumn(from, to int) error {
+	if err := t.HasValidColumn(from); err != nil {
+		return err
+	}
+	if err := t.HasValidColumn(to); err != nil {
+		return err
+	}
+	var order []int
+	for i := 0; i < len(t.ColDefs); i++ {
+		if i != from {
+			order = append(order, i)
+		}
+	}
+	order = append(order[:to], append([]int{from}, order[to:]...)...)
+	var cds []ColumnDef
+	for i := 0; i < len(order); i++ {
+		cds = append(cds, t.ColDefs[order[i]])
+	}
+	t.setColumns(cds, order)
+	return nil
+}
+
+// setColumns replaces the column definitions with cds and rearranges the cells
+// of every row to match. order[i] is the old index of new column i, or -1 for
+// a new column whose cells start out empty. Columns that are not in order are
+// dropped. The column-indexed css keys and format rules are renumbered the
+// same way.
+func (t *Table) setColumns(cds []ColumnDef, order []int) {
+	for r := 0; r < len(t.Row); r++ {
+		col := make([]Cell, len(order))
+		for i := 0; i < len(order); i++ {
+			if order[i] >= 0 && order[i] < len(t.Row[r].Col) {
+				col[i] = t.Row[r].Col[order[i]]
+			}
+		}
+		t.Row[r].Col = col
+	}
+
+	newIndex := map[int]int{}
+	for i := 0; i < len(order); i++ {
+		if order[i] >= 0 {
+			newIndex[order[i]] = i
+		}
+	}
+	t.remapColumns(newIndex)
+	t.ColDefs = cds
+}
+
+// remapColumns renumbers everything in the table that refers to a column by its
+// index. newIndex maps old column indeces to new ones; references to columns
+// that are not in newIndex, such as the format rules of a deleted column, are
+// removed.
+func (t *Table) remapColumns(newIndex map[int]int) {
+	var rules []FormatRule
+	for i := 0; i < len(t.formatRules); i++ {
+		r := t.formatRules[i]
+		if r.Col >= 0 {
+			n, ok := newIndex[r.Col]
+			if !ok {
+				continue // the column was deleted
+			}
+			r.Col = n
+		}
+		rules = append(rules, r)
+	}
+	t.formatRules = rules
+
+	if t.CSS == nil {
+		return
+	}
+	css := make(map[string]map[string]*CSSProperty, len(t.CSS))
+	for k, v := range t.CSS {
+		var prefix, col string
+		switch {
+		case strings.HasPrefix(k, `header-`):
+			prefix, col = `header-`, strings.TrimPrefix(k, `header-`)
+		case strings.HasPrefix(k, `row:`) && strings.Contains(k, `-col:`):
+			i := strings.LastIndex(k, `-col:`) + len(`-col:`)
+			prefix, col = k[:i], k[i:]
+		default:
+			css[k] = v
+			continue
+		}
+		old, err := strconv.Atoi(col)
+		if err != nil {
+			css[k] = v
+			continue
+		}
+		if n, ok := newIndex[old]; ok {
+			css[prefix+strconv.Itoa(n)] = v
+		}
+	}
+	t.CSS = css
+}
@@ -0,0 +1,72 @@
+package gotable
+
+import (
+	"testing"
+)
+
+func TestColumnEdits(t *testing.T) {
+	var tbl Table
+	tbl.Init()
+	tbl.AddColumn("A", 5, CELLINT, COLJUSTIFYRIGHT)
+	tbl.AddColumn("B", 5, CELLSTRING, COLJUSTIFYLEFT)
+	tbl.AddColumn("C", 5, CELLFLOAT, COLJUSTIFYRIGHT)
+	for i := 0; i < 3; i++ {
+		tbl.AddRow()
+		tbl.Puti(-1, 0, int64(i))
+		tbl.Puts(-1, 1, "b")
+		tbl.Putf(-1, 2, float64(i)/2)
+	}
+	red := []*CSSProperty{{Name: "color", Value: "red"}}
+	tbl.SetCellCSS(1, 2, red)
+	tbl.SetHeaderCellCSS(2, red)
+
+	titles := func() string {
+		s := ""
+		for i := 0; i < len(tbl.ColDefs); i++ {
+			s += tbl.ColDefs[i].ColTitle
+		}
+		return s
+	}
+
+	if err := tbl.InsertColumn(1, "X", 5, CELLINT, COLJUSTIFYRIGHT); err != nil {
+		t.Fatalf("columns_test: InsertColumn returned error: %s\n", err.Error())
+	}
+	if titles() != "AXBC" || len(tbl.Row[0].Col) != 4 || tbl.Type(0, 1) != 0 || tbl.Getf(1, 3) != 0.5 {
+		t.Errorf("columns_test: unexpected table after InsertColumn: %s\n%s", titles(), tbl.String())
+	}
+	if _, ok := tbl.CSS[tbl.getCSSMapKeyForCell(1, 3)]; !ok {
+		t.Errorf("columns_test: cell css did not move with its column\n")
+	}
+	if _, ok := tbl.CSS[tbl.getCSSMapKeyForHeaderCell(3)]; !ok {
+		t.Errorf("columns_test: header css did not move with its column\n")
+	}
+	if err := tbl.InsertColumn(4, "Z", 5, CELLINT, COLJUSTIFYRIGHT); err != nil || titles() != "AXBCZ" {
+		t.Errorf("columns_test: could not append column with InsertColumn: %s\n", titles())
+	}
+	if err := tbl.InsertColumn(9, "Q", 5, CELLINT, COLJUSTIFYRIGHT); err == nil {
+		t.Errorf("columns_test: expected an error inserting past the end\n")
+	}
+
+	if err := tbl.MoveColumn(3, 0); err != nil {
+		t.Fatalf("columns_test: MoveColumn returned error: %s\n", err.Error())
+	}
+	if titles() != "CAXBZ" || tbl.Getf(1, 0) != 0.5 || tbl.Geti(2, 1) != 2 {
+		t.Errorf("columns_test: unexpected table after MoveColumn: %s\n%s", titles(), tbl.String())
+	}
+	if _, ok := tbl.CSS[tbl.getCSSMapKeyForCell(1, 0)]; !ok {
+		t.Errorf("columns_test: cell css did not move with its column\n")
+	}
+
+	if err := tbl.DeleteColumn(0); err != nil {
+		t.Fatalf("columns_test: DeleteColumn returned error: %s\n", err.Error())
+	}
+	if titles() != "AXBZ" || len(tbl.Row[2].Col) != 4 {
+		t.Errorf("columns_test: unexpected table after DeleteColumn: %s\n", titles())
+	}
+	for k := range tbl.CSS {
+		t.Errorf("columns_test: Expected css of deleted column to be removed, found %s\n", k)
+	}
+	if s := tbl.String(); len(s) == 0 {
+		t.Errorf("columns_test: no text output\n")
+	}
+}
@@ -5,8 +5,6 @@ import (
 	"encoding/csv"
 	"fmt"
 	"io"
-
-	"github.com/dustin/go-humanize"
 )
 
 // CSVTable struct used to prepare table in html version
@@ -174,16 +172,19 @@ func (ct *CSVTable) formatRow(row int) ([]string, error) {
 
 		switch ct.Table.Row[row].Col[i].Type {
 		case CELLFLOAT:
-			tRow = append(tRow, fmt.Sprintf(ct.Table.ColDefs[i].Pfmt, humanize.FormatFloat("#,###.##", ct.Table.Row[row].Col[i].Fval)))
+			tRow = append(tRow, fmt.Sprintf(ct.Table.ColDefs[i].Pfmt, ct.Table.floatString(i, ct.Table.Row[row].Col[i].Fval)))
 		case CELLINT:
-			tRow = append(tRow, fmt.Sprintf(ct.Table.ColDefs[i].Pfmt, ct.Table.Row[row].Col[i].Ival))
+			tRow = append(tRow, ct.Table.sprintInt(i, ct.Table.Row[row].Col[i].Ival))
+		case CELLDECIMAL:
+			// amounts are padded to the column width but never cut
+			tRow = append(tRow, fmt.Sprintf("%*s", ct.Table.ColDefs[i].Width, ct.Table.decimalString(i, &ct.Table.Row[row].Col[i])))
 		case CELLSTRING:
 			// FOR CSV, APPEND FULL STRING, THERE ARE NO MULTILINE STRING IN THIS
 			tRow = append(tRow, ct.Table.Row[row].Col[i].Sval)
-		case CELLDATE:
-			tRow = append(tRow, fmt.Sprintf("%*.*s", ct.Table.ColDefs[i].Width, ct.Table.ColDefs[i].Width, ct.Table.Row[row].Col[i].Dval.Format(ct.Table.DateFmt)))
-		case CELLDATETIME:
-			tRow = append(tRow, fmt.Sprintf("%*.*s", ct.Table.ColDefs[i].Width, ct.Table.ColDefs[i].Width, ct.Table.Row[row].Col[i].Dval.Format(ct.Table.DateTimeFmt)))
+		case CELLDATE, CELLDATETIME:
+			tRow = append(tRow, fmt.Sprintf("%*.*s", ct.Table.ColDefs[i].Width, ct.Table.ColDefs[i].Width, ct.Table.dateString(i, &ct.Table.Row[row].Col[i])))
+		case CELLNULL:
+			tRow = append(tRow, ct.Table.csvNullText)
 		default:
 			tRow = append(tRow, mkstr(ct.Table.ColDefs[i].Width, ' '))
 		}
@@ -0,0 +1,273 @@
+package gotable
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CSVReadOptions controls how ReadCSV builds a table
+type CSVReadOptions struct {
+	NoHeader    bool           // the first record is data. Columns are titled "Column 1", "Column 2", ...
+	Types       map[string]int // cell types to use for the named columns instead of inferring them
+	NullText    string         // fields with this value become null cells. "" means no field is null
+	DateFmt     string         // format of CELLDATE values. Default: the Table default
+	DateTimeFmt string         // format of CELLDATETIME values. Default: the Table default
+	Comma       rune           // field separator. Default: ','
+}
+
+// csvNumber matches an optionally signed number with an optional currency
+// symbol and thousands separators, e.g. "-$1,234.50"
+var csvNumber = regexp.MustCompile(`^([-+]?)([^0-9.,+-]*)(\d{1,3}(,\d{3})+|\d+)?(\.\d+)?$`)
+
+// parseCSVNumber returns the number in s without its currency symbol and
+// thousands separators, and the symbol. Negative numbers can also be written
+// in parentheses, "($1,234.50)", or with a trailing CR, "1,234.50 CR", as
+// NEGPARENS and NEGCR print them. ok is false if s is not a number.
+func parseCSVNumber(s string) (num, symbol string, ok bool) {
+	neg := false
+	if strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")") {
+		s, neg = s[1:len(s)-1], true
+	} else if strings.HasSuffix(s, " CR") {
+		s, neg = strings.TrimSuffix(s, " CR"), true
+	}
+	m := csvNumber.FindStringSubmatch(s)
+	if m == nil || (m[3] == "" && m[5] == "") || (neg && m[1] != "") {
+		return "", "", false
+	}
+	sign := m[1]
+	if neg {
+		sign = "-"
+	}
+	return sign + strings.Replace(m[3], ",", "", -1) + m[5], m[2], true
+}
+
+// csvCellType returns the best cell type for the non-empty values in vals
+func csvCellType(vals []string, datefmt, datetimefmt string) int {
+	isInt, isFloat, isDate, isDateTime := true, true, true, true
+	n := 0
+	for i := 0; i < len(vals); i++ {
+		if vals[i] == "" {
+			continue
+		}
+		n++
+		num, symbol, ok := parseCSVNumber(vals[i])
+		if !ok || symbol != "" {
+			isInt, isFloat = false, false
+		} else if strings.Contains(num, ".") {
+			isInt = false
+		}
+		if _, err := time.Parse(datefmt, vals[i]); err != nil {
+			isDate = false
+		}
+		if _, err := time.Parse(datetimefmt, vals[i]); err != nil {
+			isDateTime = false
+		}
+	}
+	switch {
+	case n == 0:
+		return CELLSTRING
+	case isInt:
+		return CELLINT
+	case isFloat:
+		return CELLFLOAT
+	case isDate:
+		return CELLDATE
+	case isDateTime:
+		return CELLDATETIME
+	}
+	return CELLSTRING
+}
+
+// csvDecimals returns the largest number of digits after the decimal point of
+// the numbers in vals
+func csvDecimals(vals []string) int {
+	n := 0
+	for i := 0; i < len(vals); i++ {
+		num, _, ok := parseCSVNumber(vals[i])
+		if !ok {
+			continue
+		}
+		if j := strings.IndexByte(num, '.'); j >= 0 && len(num)-j-1 > n {
+			n = len(num) - j - 1
+		}
+	}
+	return n
+}
+
+// ReadCSV creates a table from csv data, such as the output of CSVprintTable.
+// Records at the start of the data that have a single field, when the records
+// after them have more, are taken to be the title and sections the way
+// CSVprintTable writes them: they set Title, Section1, Section2 and Section3 in
+// that order. The next record holds the column titles, unless opts.NoHeader is
+// set. Fields are trimmed of leading and trailing spaces and empty fields
+// become empty cells. The type of each column is inferred from its values:
+// CELLINT, CELLFLOAT (thousands separators are allowed), CELLDATE, CELLDATETIME
+// or CELLSTRING, unless opts.Types says otherwise. Numbers are read in the
+// en-US format: "," groups thousands and "." is the decimal point, and
+// negative numbers have a leading minus sign, parentheses or a trailing " CR".
+// Numbers written with other number formats, such as de-DE "1.234,50" or
+// fr-FR "1 234,50", are read as strings. Numbers in a CELLDECIMAL
+// column may have a currency symbol, which becomes the column's symbol, and
+// all of them get the scale of the one with the most digits after the decimal
+// point. If opts is nil the default options are used.
+func ReadCSV(r io.Reader, opts *CSVReadOptions) (*Table, error) {
+	funcname := "ReadCSV"
+	if opts == nil {
+		opts = &CSVReadOptions{}
+	}
+	var t Table
+	t.Init()
+	if opts.DateFmt != "" {
+		t.DateFmt = opts.DateFmt
+	}
+	if opts.DateTimeFmt != "" {
+		t.DateTimeFmt = opts.DateTimeFmt
+	}
+
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	if opts.Comma != 0 {
+		cr.Comma = opts.Comma
+	}
+	recs, err := cr.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", funcname, err.Error())
+	}
+
+	// title and sections
+	ncols := 0
+	for i := 0; i < len(recs); i++ {
+		if len(recs[i]) > ncols {
+			ncols = len(recs[i])
+		}
+	}
+	titles := []*string{&t.Title, &t.Section1, &t.Section2, &t.Section3}
+	for len(recs) > 0 && len(recs[0]) == 1 && ncols > 1 && len(titles) > 0 {
+		*titles[0] = recs[0][0]
+		titles = titles[1:]
+		recs = recs[1:]
+	}
+
+	// column titles
+	var hdr []string
+	if !opts.NoHeader && len(recs) > 0 {
+		hdr = recs[0]
+		recs = recs[1:]
+	}
+	for i := len(hdr); i < ncols; i++ {
+		hdr = append(hdr, "Column "+strconv.Itoa(i+1))
+	}
+
+	// trim the values, take out the nulls, and find the column types
+	isNull := map[[2]int]bool{}
+	for r := 0; r < len(recs); r++ {
+		for c := 0; c < len(recs[r]); c++ {
+			recs[r][c] = strings.TrimSpace(recs[r][c])
+			if opts.NullText != "" && recs[r][c] == opts.NullText {
+				recs[r][c] = ""
+				isNull[[2]int{r, c}] = true
+			}
+		}
+	}
+	for c := 0; c < len(hdr); c++ {
+		title := strings.TrimSpace(hdr[c])
+		var vals []string
+		for r := 0; r < len(recs); r++ {
+			if c < len(recs[r]) {
+				vals = append(vals, recs[r][c])
+			}
+		}
+		celltype, ok := opts.Types[title]
+		if !ok {
+			celltype = csvCellType(vals, t.DateFmt, t.DateTimeFmt)
+		}
+		justify := COLJUSTIFYLEFT
+		if celltype == CELLINT || celltype == CELLFLOAT || celltype == CELLDECIMAL {
+			justify = COLJUSTIFYRIGHT
+		}
+		t.AddColumn(title, 1, celltype, justify)
+
+		// every value of a decimal column gets the same scale, that of
+		// the value with the most digits after the decimal point
+		if celltype == CELLDECIMAL {
+			if n := csvDecimals(vals); n > t.ColDefs[c].Fdecimals {
+				t.ColDefs[c].Fdecimals = n
+			}
+		}
+	}
+
+	for r := 0; r < len(recs); r++ {
+		t.AddRow()
+		for c := 0; c < len(recs[r]) && c < len(t.ColDefs); c++ {
+			switch {
+			case isNull[[2]int{r, c}]:
+				t.PutNull(-1, c)
+			case recs[r][c] != "":
+				if err := t.putCSVValue(c, recs[r][c]); err != nil {
+					return nil, fmt.Errorf("%s: record %d, column %q: %s", funcname, r+1, t.ColDefs[c].ColTitle, err.Error())
+				}
+			}
+		}
+	}
+
+	for i := 0; i < len(t.ColDefs); i++ {
+		t.setColumnWidth(i, 0)
+	}
+	return &t, nil
+}
+
+// putCSVValue stores the csv field s in column col of the last row, converting
+// it to the type of the column
+func (t *Table) putCSVValue(col int, s string) error {
+	cd := &t.ColDefs[col]
+	switch cd.CellType {
+	case CELLINT, CELLFLOAT, CELLDECIMAL:
+		num, symbol, ok := parseCSVNumber(s)
+		if !ok {
+			return fmt.Errorf("%q is not a number", s)
+		}
+		switch cd.CellType {
+		case CELLINT:
+			i, err := strconv.ParseInt(num, 10, 64)
+			if err != nil {
+				return err
+			}
+			t.Puti(-1, col, i)
+		case CELLFLOAT:
+			f, err := strconv.ParseFloat(num, 64)
+			if err != nil {
+				return err
+			}
+			t.Putf(-1, col, f)
+		default:
+			d, err := ParseDecimal(num)
+			if err != nil {
+				return err
+			}
+			if symbol != "" {
+				cd.Currency = symbol
+			}
+			if err := t.putm(len(t.Row)-1, col, d); err != nil {
+				return err
+			}
+		}
+	case CELLDATE, CELLDATETIME:
+		layout := t.DateFmt
+		if cd.CellType == CELLDATETIME {
+			layout = t.DateTimeFmt
+		}
+		d, err := time.Parse(layout, s)
+		if err != nil {
+			return err
+		}
+		t.putdint(-1, col, d, cd.CellType)
+	default:
+		t.Puts(-1, col, s)
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package gotable
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadCSV(t *testing.T) {
+	var tbl Table
+	tbl.Init()
+	tbl.SetTitle("Rent Roll")
+	tbl.SetSection1("Building A")
+	tbl.SetSection2("March 2018")
+	tbl.AddColumn("Tenant", 20, CELLSTRING, COLJUSTIFYLEFT)
+	tbl.AddColumn("Units", 6, CELLINT, COLJUSTIFYRIGHT)
+	tbl.AddColumn("Rent", 12, CELLFLOAT, COLJUSTIFYRIGHT)
+	tbl.AddColumn("Move In", 10, CELLDATE, COLJUSTIFYLEFT)
+	tbl.AddColumn("Last Payment", 25, CELLDATETIME, COLJUSTIFYLEFT)
+
+	d := time.Date(2017, time.June, 1, 0, 0, 0, 0, time.UTC)
+	dt := time.Date(2018, time.March, 3, 14, 30, 0, 0, time.UTC)
+	var data = []struct {
+		tenant string
+		units  int64
+		rent   float64
+	}{
+		{"Smith, John", 2, 1250.5}, {"Acme \"Widgets\"", 10, 123456.78}, {"Jones", -1, 0},
+	}
+	for i := 0; i < len(data); i++ {
+		tbl.AddRow()
+		tbl.Puts(-1, 0, data[i].tenant)
+		tbl.Puti(-1, 1, data[i].units)
+		tbl.Putf(-1, 2, data[i].rent)
+		tbl.Putd(-1, 3, d.AddDate(0, i, 0))
+		tbl.Putdt(-1, 4, dt.AddDate(0, 0, i))
+	}
+	tbl.AddRow() // a total row with empty cells
+	tbl.Put(-1, 2, tbl.Sum(2))
+
+	var b bytes.Buffer
+	tbl.CSVprintTable(&b)
+	r, err := ReadCSV(&b, nil)
+	if err != nil {
+		t.Errorf("csvread_test: ReadCSV: %s\n", err.Error())
+		return
+	}
+	if r.Title != "Rent Roll" || r.Section1 != "Building A" || r.Section2 != "March 2018" || r.Section3 != "" {
+		t.Errorf("csvread_test: wrong title or sections: %q %q %q %q\n", r.Title, r.Section1, r.Section2, r.Section3)
+	}
+	if r.ColCount() != tbl.ColCount() || r.RowCount() != tbl.RowCount() {
+		t.Errorf("csvread_test: expected %d columns and %d rows, found %d and %d\n", tbl.ColCount(), tbl.RowCount(), r.ColCount(), r.RowCount())
+		return
+	}
+	for c := 0; c < tbl.ColCount(); c++ {
+		if r.ColDefs[c].ColTitle != tbl.ColDefs[c].ColTitle || r.ColDefs[c].CellType != tbl.ColDefs[c].CellType {
+			t.Errorf("csvread_test: column %d: expected %q type %d, found %q type %d\n", c,
+				tbl.ColDefs[c].ColTitle, tbl.ColDefs[c].CellType, r.ColDefs[c].ColTitle, r.ColDefs[c].CellType)
+		}
+		for i := 0; i < tbl.RowCount(); i++ {
+			a, b := tbl.Get(i, c), r.Get(i, c)
+			if compareCells(&a, &b) != 0 {
+				t.Errorf("csvread_test: cell %d,%d: expected %#v, found %#v\n", i, c, a, b)
+			}
+		}
+	}
+
+	// no header, overrides, nulls and currency
+	s := "Smith,\"$1,234.50\",n/a\nJones,-$0.75,7\n"
+	r, err = ReadCSV(strings.NewReader(s), &CSVReadOptions{
+		NoHeader: true,
+		NullText: "n/a",
+		Types:    map[string]int{"Column 2": CELLDECIMAL},
+	})
+	if err != nil {
+		t.Errorf("csvread_test: ReadCSV: %s\n", err.Error())
+		return
+	}
+	if r.RowCount() != 2 || r.ColDefs[2].ColTitle != "Column 3" || r.ColDefs[2].CellType != CELLINT {
+		t.Errorf("csvread_test: NoHeader: unexpected columns %#v\n", r.ColDefs)
+	}
+	if m := r.Sum(1); m.Type != CELLDECIMAL || m.Mval.String() != "1233.75" || r.ColDefs[1].Currency != "$" {
+		t.Errorf("csvread_test: expected a decimal sum of 1233.75 in dollars, found %#v\n", m)
+	}
+	if !r.IsNull(0, 2) || r.Get(0, 2).Type != CELLNULL || r.Geti(1, 2) != 7 {
+		t.Errorf("csvread_test: expected a null and 7, found %#v %#v\n", r.Get(0, 2), r.Get(1, 2))
+	}
+
+	// every value of a decimal column has the scale of the longest one
+	r, err = ReadCSV(strings.NewReader("Rent\n12.5\n3\n0.125\n"), &CSVReadOptions{Types: map[string]int{"Rent": CELLDECIMAL}})
+	if err != nil {
+		t.Errorf("csvread_test: ReadCSV: %s\n", err.Error())
+		return
+	}
+	for i, expect := range []string{"12.500", "3.000", "0.125"} {
+		if d := r.Getm(i, 0); d.Scale != 3 || d.String() != expect {
+			t.Errorf("csvread_test: row %d: expected %s, found %s with scale %d\n", i, expect, d.String(), d.Scale)
+		}
+	}
+
+	// bad data for an override
+	_, err = ReadCSV(strings.NewReader("A,B\nx,y\n"), &CSVReadOptions{Types: map[string]int{"B": CELLINT}})
+	if err == nil {
+		t.Errorf("csvread_test: expected an error reading y as an int\n")
+	}
+}
+
+func TestReadCSVNegatives(t *testing.T) {
+	var tbl Table
+	tbl.Init()
+	tbl.AddColumn("Parens", 12, CELLFLOAT, COLJUSTIFYRIGHT)
+	tbl.AddColumn("CR", 12, CELLFLOAT, COLJUSTIFYRIGHT)
+	tbl.AddColumn("Rent", 14, CELLDECIMAL, COLJUSTIFYRIGHT)
+	tbl.SetColNumberFormat(0, &NumberFormat{Grouping: ",", Negative: NEGPARENS})
+	tbl.SetColNumberFormat(1, &NumberFormat{Grouping: ",", Negative: NEGCR})
+	tbl.SetColNumberFormat(2, &NumberFormat{Grouping: ",", Negative: NEGPARENS})
+	tbl.SetColDecimal(2, 2, "$", ROUNDHALFUP)
+	tbl.AddRow()
+	tbl.Putf(-1, 0, -1234.5)
+	tbl.Putf(-1, 1, -1234.5)
+	tbl.Putm(-1, 2, NewDecimal(-123450, 2))
+	tbl.AddRow()
+	tbl.Putf(-1, 0, 7)
+	tbl.Putf(-1, 1, 7)
+	tbl.Putm(-1, 2, NewDecimal(700, 2))
+
+	var b bytes.Buffer
+	tbl.CSVprintTable(&b)
+	r, err := ReadCSV(&b, &CSVReadOptions{Types: map[string]int{"Rent": CELLDECIMAL}})
+	if err != nil {
+		t.Fatalf("csvread_test: ReadCSV: %s\n", err.Error())
+	}
+	if r.ColDefs[0].CellType != CELLFLOAT || r.ColDefs[1].CellType != CELLFLOAT {
+		t.Errorf("csvread_test: expected float columns, found %#v\n", r.ColDefs)
+	}
+	if r.Getf(0, 0) != -1234.5 || r.Getf(0, 1) != -1234.5 || r.Getm(0, 2).String() != "-1234.50" || r.Getf(1, 0) != 7 {
+		t.Errorf("csvread_test: unexpected values:\n%s\n", r.String())
+	}
+	for _, s := range []string{"-(1.00)", "(-1.00)", "- 1.00 CR"} {
+		if _, _, ok := parseCSVNumber(s); ok {
+			t.Errorf("csvread_test: expected %q not to be a number\n", s)
+		}
+	}
+}
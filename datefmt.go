@@ -0,0 +1,120 @@
+package gotable
+
+import (
+	"fmt"
+	"time"
+)
+
+// DATERELATIVE can be used as the date format of a column to print its values
+// relative to the current time, e.g. "yesterday", "3 days ago" or "in 2 hours"
+const DATERELATIVE = "relative"
+
+// timeNow returns the current time. Tests replace it to print relative dates
+// against a fixed time.
+var timeNow = time.Now
+
+// SetColDateFormat sets the layout used to print the CELLDATE and CELLDATETIME
+// values of column col, e.g. "Jan 2" or time.RFC3339, or DATERELATIVE. An
+// empty layout uses the DateFmt or DateTimeFmt of the table. If loc is not nil
+// CELLDATETIME values are shown in time zone loc instead of the Location of
+// the table. CELLDATE values are calendar days and are never converted.
+func (t *Table) SetColDateFormat(col int, layout string, loc *time.Location) error {
+	if err := t.HasValidColumn(col); err != nil {
+		return err
+	}
+	t.ColDefs[col].DateFmt = layout
+	t.ColDefs[col].Location = loc
+	return nil
+}
+
+// dateLayout returns the layout used to print the date in c in column col
+func (t *Table) dateLayout(col int, c *Cell) string {
+	if layout := t.ColDefs[col].DateFmt; layout != "" {
+		return layout
+	}
+	if c.Type == CELLDATETIME {
+		return t.DateTimeFmt
+	}
+	return t.DateFmt
+}
+
+// dateValue returns the date in c as it is shown in column col, converted to
+// the display time zone if it is a CELLDATETIME value
+func (t *Table) dateValue(col int, c *Cell) time.Time {
+	if c.Type != CELLDATETIME {
+		return c.Dval
+	}
+	loc := t.ColDefs[col].Location
+	if loc == nil {
+		loc = t.Location
+	}
+	if loc == nil {
+		return c.Dval
+	}
+	return c.Dval.In(loc)
+}
+
+// dateString returns the date in c formatted for column col, without padding
+func (t *Table) dateString(col int, c *Cell) string {
+	d := t.dateValue(col, c)
+	layout := t.dateLayout(col, c)
+	if layout != DATERELATIVE {
+		return d.Format(layout)
+	}
+	now := timeNow().In(d.Location())
+	if c.Type == CELLDATE {
+		return relativeDays(d, now)
+	}
+	return relativeTime(now.Sub(d))
+}
+
+// relativeDays returns the calendar day d relative to the day of now
+func relativeDays(d, now time.Time) string {
+	day := time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, time.UTC)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	days := int(today.Sub(day).Hours() / 24)
+	switch days {
+	case 0:
+		return "today"
+	case 1:
+		return "yesterday"
+	case -1:
+		return "tomorrow"
+	}
+	return relativeTime(time.Duration(days) * 24 * time.Hour)
+}
+
+// relativeTime returns the time that is ago before now in words, e.g.
+// "3 days ago", or "in 3 days" if ago is negative
+func relativeTime(ago time.Duration) string {
+	future := ago < 0
+	if future {
+		ago = -ago
+	}
+	units := []struct {
+		name string
+		d    time.Duration
+	}{
+		{"year", 365 * 24 * time.Hour},
+		{"month", 30 * 24 * time.Hour},
+		{"week", 7 * 24 * time.Hour},
+		{"day", 24 * time.Hour},
+		{"hour", time.Hour},
+		{"minute", time.Minute},
+	}
+	for i := 0; i < len(units); i++ {
+		n := int(ago / units[i].d)
+		if n == 0 {
+			continue
+		}
+		s := fmt.Sprintf("%d %ss", n, units[i].name)
+		if n == 1 {
+			s = "1 " + units[i].name
+		}
+		if future {
+			return "in " + s
+		}
+		return s + " ago"
+	}
+	return "just now"
+}
@@ -0,0 +1,128 @@
+package gotable
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDateFormat(t *testing.T) {
+	now := time.Date(2020, time.March, 10, 12, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	tokyo := time.FixedZone("JST", 9*3600)
+	ny := time.FixedZone("EST", -5*3600)
+
+	var tbl Table
+	tbl.Init()
+	tbl.AddColumn("Due", 6, CELLDATE, COLJUSTIFYLEFT)
+	tbl.AddColumn("Posted", 25, CELLDATETIME, COLJUSTIFYLEFT)
+	tbl.AddColumn("Age", 12, CELLDATETIME, COLJUSTIFYLEFT)
+	tbl.AddColumn("When", 12, CELLDATE, COLJUSTIFYLEFT)
+	tbl.SetColDateFormat(0, "Jan 2", nil)
+	tbl.SetColDateFormat(1, time.RFC3339, ny)
+	tbl.SetColDateFormat(2, DATERELATIVE, nil)
+	tbl.SetColDateFormat(3, DATERELATIVE, nil)
+	if err := tbl.SetColDateFormat(9, "", nil); err == nil {
+		t.Errorf("datefmt_test: expected an error for an invalid column\n")
+	}
+
+	// 10:00 in Tokyo is before 05:00 UTC
+	tbl.AddRow()
+	tbl.Putd(-1, 0, time.Date(2020, time.March, 2, 0, 0, 0, 0, time.UTC))
+	tbl.Putdt(-1, 1, time.Date(2020, time.March, 9, 5, 0, 0, 0, time.UTC))
+	tbl.Putdt(-1, 2, now.Add(-3*24*time.Hour))
+	tbl.Putd(-1, 3, time.Date(2020, time.March, 9, 0, 0, 0, 0, time.UTC))
+	tbl.AddRow()
+	tbl.Putd(-1, 0, time.Date(2020, time.April, 15, 0, 0, 0, 0, time.UTC))
+	tbl.Putdt(-1, 1, time.Date(2020, time.March, 9, 10, 0, 0, 0, tokyo))
+	tbl.Putdt(-1, 2, now.Add(-90*time.Minute))
+	tbl.Putd(-1, 3, time.Date(2020, time.March, 14, 0, 0, 0, 0, time.UTC))
+
+	tbl.Sort(0, tbl.RowCount()-1, 1)
+	rows := [][]string{
+		{"Apr 15", "2020-03-08T20:00:00-05:00", "1 hour ago", "in 4 days"},
+		{"Mar 2", "2020-03-09T00:00:00-05:00", "3 days ago", "yesterday"},
+	}
+	for r := 0; r < len(rows); r++ {
+		for c := 0; c < len(rows[r]); c++ {
+			if s := tbl.cellText(c, &tbl.Row[r].Col[c]); s != rows[r][c] {
+				t.Errorf("datefmt_test: row %d col %d: expected %q, found %q\n", r, c, rows[r][c], s)
+			}
+		}
+	}
+
+	s, _ := tbl.SprintTable()
+	if !strings.Contains(s, " Mar 2  2020-03-09T00:00:00-05:00    3 days ago     yesterday") {
+		t.Errorf("datefmt_test: unexpected text output:\n%s\n", s)
+	}
+	var b bytes.Buffer
+	tbl.CSVprintTable(&b)
+	if !strings.Contains(b.String(), "2020-03-08T20:00:00-05:00") {
+		t.Errorf("datefmt_test: unexpected CSV output:\n%s\n", b.String())
+	}
+
+	// the table location is used by columns without one
+	tbl.Location = tokyo
+	tbl.SetColDateFormat(1, "", nil)
+	if s := tbl.cellText(1, &tbl.Row[1].Col[1]); s != "03/09/2020 14:00:00 JST" {
+		t.Errorf("datefmt_test: table location: found %q\n", s)
+	}
+	if s := tbl.cellText(0, &tbl.Row[1].Col[0]); s != "Mar 2" {
+		t.Errorf("datefmt_test: dates should not be converted: found %q\n", s)
+	}
+
+	durations := []struct {
+		d      time.Duration
+		expect string
+	}{
+		{10 * time.Second, "just now"},
+		{2 * time.Minute, "2 minutes ago"},
+		{-26 * time.Hour, "in 1 day"},
+		{15 * 24 * time.Hour, "2 weeks ago"},
+		{400 * 24 * time.Hour, "1 year ago"},
+	}
+	for i := 0; i < len(durations); i++ {
+		if s := relativeTime(durations[i].d); s != durations[i].expect {
+			t.Errorf("datefmt_test: relativeTime(%s): expected %q, found %q\n", durations[i].d, durations[i].expect, s)
+		}
+	}
+}
+
+func TestDateFormatPivotGroupBy(t *testing.T) {
+	var tbl Table
+	tbl.Init()
+	tbl.AddColumn("Due", 6, CELLDATE, COLJUSTIFYLEFT)
+	tbl.AddColumn("Posted", 6, CELLDATE, COLJUSTIFYLEFT)
+	tbl.AddColumn("Tenant", 8, CELLSTRING, COLJUSTIFYLEFT)
+	tbl.AddColumn("Amount", 10, CELLFLOAT, COLJUSTIFYRIGHT)
+	tbl.SetColDateFormat(0, "Jan 2", nil)
+	tbl.SetColDateFormat(1, "Mon", nil)
+	for i := 0; i < 3; i++ {
+		tbl.AddRow()
+		tbl.Putd(-1, 0, time.Date(2020, time.March, 1+i%2, 0, 0, 0, 0, time.UTC))
+		tbl.Putd(-1, 1, time.Date(2020, time.March, 2, 0, 0, 0, 0, time.UTC))
+		tbl.Puts(-1, 2, "Smith")
+		tbl.Putf(-1, 3, 100)
+	}
+
+	p, err := tbl.Pivot(0, 1, 3, AggSum)
+	if err != nil {
+		t.Fatalf("datefmt_test: Pivot returned error: %s\n", err.Error())
+	}
+	if p.ColDefs[0].DateFmt != "Jan 2" || p.cellText(0, &p.Row[0].Col[0]) != "Mar 1" {
+		t.Errorf("datefmt_test: expected the pivot row keys to keep their format, found %q\n", p.cellText(0, &p.Row[0].Col[0]))
+	}
+	if p.ColDefs[1].ColTitle != "Mon" {
+		t.Errorf("datefmt_test: expected the column key title Mon, found %q\n", p.ColDefs[1].ColTitle)
+	}
+
+	if err := tbl.GroupBy([]int{0}, map[int]AggFunc{3: AggSum}, &GroupByOptions{SubtotalFmt: "%s Total", NoGrandTotal: true}); err != nil {
+		t.Fatalf("datefmt_test: GroupBy returned error: %s\n", err.Error())
+	}
+	if s := tbl.Gets(2, 2); s != "Mar 1 Total" {
+		t.Errorf("datefmt_test: expected the subtotal label Mar 1 Total, found %q\n", s)
+	}
+}
@@ -0,0 +1,318 @@
+package gotable
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ROUNDHALFUP et. al. are the rounding modes used when a Decimal loses digits
+const (
+	ROUNDHALFUP   = 0 // round to nearest, ties away from zero. This is the default
+	ROUNDHALFEVEN = 1 // round to nearest, ties to the even digit (banker's rounding)
+	ROUNDDOWN     = 2 // truncate toward zero
+	ROUNDUP       = 3 // round away from zero
+)
+
+// Decimal is an exact fixed-point number: Units / 10^Scale. For example, with
+// a Scale of 2, $12.34 is stored as 1234 Units. Decimals are stored in Cells of
+// type CELLDECIMAL; adding them never loses a cent the way float64 can.
+type Decimal struct {
+	Units int64 // the value in units of 10^-Scale
+	Scale int   // the number of digits after the decimal point
+}
+
+// NewDecimal returns the Decimal units / 10^scale
+func NewDecimal(units int64, scale int) Decimal {
+	return Decimal{Units: units, Scale: scale}
+}
+
+// ParseDecimal converts a string like "-1234.5" to a Decimal. The scale of the
+// result is the number of digits after the decimal point.
+func ParseDecimal(s string) (Decimal, error) {
+	var d Decimal
+	s = strings.TrimSpace(s)
+	neg := strings.HasPrefix(s, "-")
+	digits := strings.TrimLeft(s, "+-")
+	if len(s)-len(digits) > 1 {
+		return d, fmt.Errorf("ParseDecimal: invalid number %q", s)
+	}
+	if i := strings.IndexByte(digits, '.'); i >= 0 {
+		d.Scale = len(digits) - i - 1
+		digits = digits[:i] + digits[i+1:]
+	}
+	if digits == "" || strings.IndexFunc(digits, func(r rune) bool { return r < '0' || r > '9' }) >= 0 {
+		return Decimal{}, fmt.Errorf("ParseDecimal: invalid number %q", s)
+	}
+	u, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return Decimal{}, fmt.Errorf("ParseDecimal: %s", err.Error())
+	}
+	if neg {
+		u = -u
+	}
+	d.Units = u
+	return d, nil
+}
+
+// DecimalFromFloat converts f to a Decimal with the supplied scale, rounding
+// with mode. f is converted from its shortest decimal representation, so 2.675
+// is treated as exactly 2.675.
+func DecimalFromFloat(f float64, scale, mode int) Decimal {
+	d, err := ParseDecimal(strconv.FormatFloat(f, 'f', -1, 64))
+	if err != nil {
+		return Decimal{Scale: scale}
+	}
+	return d.Rescale(scale, mode)
+}
+
+// Rescale returns d with the supplied scale. If digits are lost, the result is
+// rounded with mode. If the value does not fit in an int64 at the new scale, d
+// is returned unchanged, so callers that scale up can compare the Scale of the
+// result with scale to detect this.
+func (d Decimal) Rescale(scale, mode int) Decimal {
+	r, err := d.rescale(scale, mode)
+	if err != nil {
+		return d
+	}
+	return r
+}
+
+// rescale returns d with the supplied scale, rounding with mode, or an error
+// if the value does not fit in an int64 at that scale. Digits are added and
+// dropped one at a time so that no power of ten overflows.
+func (d Decimal) rescale(scale, mode int) (Decimal, error) {
+	q := d.Units
+	if scale >= d.Scale {
+		for i := d.Scale; i < scale; i++ {
+			if q > math.MaxInt64/10 || q < math.MinInt64/10 {
+				return d, fmt.Errorf("%s does not fit in a decimal with %d digits after the decimal point", d.String(), scale)
+			}
+			q *= 10
+		}
+		return Decimal{Units: q, Scale: scale}, nil
+	}
+
+	// first is the first digit dropped, rest is true if any digit after it
+	// is not zero. Both carry the sign of d.
+	var first int64
+	rest := false
+	for i := scale; i < d.Scale; i++ {
+		rest = rest || first != 0
+		first = q % 10
+		q /= 10
+	}
+	sign := int64(1)
+	if first < 0 || (first == 0 && d.Units < 0) {
+		sign, first = -1, -first
+	}
+	up := false
+	switch mode {
+	case ROUNDHALFEVEN:
+		up = first > 5 || (first == 5 && (rest || q%2 != 0))
+	case ROUNDDOWN:
+	case ROUNDUP:
+		up = first != 0 || rest
+	default:
+		up = first >= 5
+	}
+	if up {
+		q += sign
+	}
+	return Decimal{Units: q, Scale: scale}, nil
+}
+
+// align returns a and b with the same scale, the larger of the two, or an
+// error if the one with the smaller scale does not fit in an int64 at it
+func align(a, b Decimal) (Decimal, Decimal, error) {
+	var err error
+	if a.Scale < b.Scale {
+		a, err = a.rescale(b.Scale, ROUNDHALFUP)
+	} else if b.Scale < a.Scale {
+		b, err = b.rescale(a.Scale, ROUNDHALFUP)
+	}
+	return a, b, err
+}
+
+// Add returns d + e. The scale of the result is the larger of the two scales.
+// An error is returned if the result does not fit in an int64 at that scale.
+func (d Decimal) Add(e Decimal) (Decimal, error) {
+	a, b, err := align(d, e)
+	if err != nil {
+		return Decimal{}, fmt.Errorf("Add: %s", err.Error())
+	}
+	u := a.Units + b.Units
+	if (b.Units > 0 && u < a.Units) || (b.Units < 0 && u > a.Units) {
+		return Decimal{}, fmt.Errorf("Add: %s + %s does not fit in a decimal", d.String(), e.String())
+	}
+	return Decimal{Units: u, Scale: a.Scale}, nil
+}
+
+// Sub returns d - e. The scale of the result is the larger of the two scales.
+// An error is returned if the result does not fit in an int64 at that scale.
+func (d Decimal) Sub(e Decimal) (Decimal, error) {
+	if e.Units == math.MinInt64 {
+		return Decimal{}, fmt.Errorf("Sub: %s - %s does not fit in a decimal", d.String(), e.String())
+	}
+	r, err := d.Add(Decimal{Units: -e.Units, Scale: e.Scale})
+	if err != nil {
+		return Decimal{}, fmt.Errorf("Sub: %s - %s does not fit in a decimal", d.String(), e.String())
+	}
+	return r, nil
+}
+
+// Cmp returns -1 if d < e, 0 if d == e, and 1 if d > e. The comparison is
+// exact for all values.
+func (d Decimal) Cmp(e Decimal) int {
+	a, b, err := align(d, e)
+	if err != nil {
+		// the value that does not fit at the larger scale has the larger
+		// magnitude, so its sign decides
+		if d.Scale < e.Scale {
+			return sign(d.Units)
+		}
+		return -sign(e.Units)
+	}
+	switch {
+	case a.Units < b.Units:
+		return -1
+	case a.Units > b.Units:
+		return 1
+	}
+	return 0
+}
+
+// sign returns -1, 0 or 1 for negative, zero and positive u
+func sign(u int64) int {
+	switch {
+	case u < 0:
+		return -1
+	case u > 0:
+		return 1
+	}
+	return 0
+}
+
+// addDecimal returns c, a sum of decimals, plus m. If the sum does not fit in
+// a Decimal, c becomes a CELLFLOAT and the rest of the sum is done in floats.
+func addDecimal(c Cell, m Decimal) Cell {
+	if c.Type != CELLFLOAT {
+		if s, err := c.Mval.Add(m); err == nil {
+			return Cell{Type: CELLDECIMAL, Mval: s}
+		}
+		c = Cell{Type: CELLFLOAT, Fval: c.Mval.Float64()}
+	}
+	c.Fval += m.Float64()
+	return c
+}
+
+// Float64 returns d as a float64
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+// String returns d with Scale digits after the decimal point, e.g. "-1234.50"
+func (d Decimal) String() string {
+	return d.format("", "", ".")
+}
+
+// normalized returns d with trailing zero digits removed, so that equal values
+// have equal Units and Scale
+func (d Decimal) normalized() Decimal {
+	for d.Scale > 0 && d.Units%10 == 0 {
+		d.Units /= 10
+		d.Scale--
+	}
+	return d
+}
+
+// format returns d as a string with the supplied currency symbol, thousands
+// separator and decimal mark
+func (d Decimal) format(symbol, sep, mark string) string {
+	nf := NumberFormat{Grouping: sep, DecimalMark: mark}
+	neg, whole, frac := d.digits()
+	return nf.format(neg, whole, frac, symbol)
+}
+
+// digits returns the sign of d and its digits before and after the decimal
+// mark
+func (d Decimal) digits() (bool, string, string) {
+	u := d.Units
+	neg := u < 0
+	if neg {
+		u = -u
+	}
+	digits := strconv.FormatInt(u, 10)
+	if len(digits) <= d.Scale {
+		digits = strings.Repeat("0", d.Scale-len(digits)+1) + digits
+	}
+	return neg, digits[:len(digits)-d.Scale], digits[len(digits)-d.Scale:]
+}
+
+// SetColDecimal sets the number of digits after the decimal point, the currency
+// symbol and the rounding mode of the CELLDECIMAL column col. Values put in the
+// column are rounded to scale digits with rounding, one of ROUNDHALFUP,
+// ROUNDHALFEVEN, ROUNDDOWN or ROUNDUP. The symbol, e.g. "$", is printed in
+// front of the values. The default is 2 digits, no symbol and ROUNDHALFUP.
+func (t *Table) SetColDecimal(col, scale int, symbol string, rounding int) error {
+	if err := t.HasValidColumn(col); err != nil {
+		return err
+	}
+	if scale < 0 || scale > 18 {
+		return fmt.Errorf("SetColDecimal: invalid scale %d", scale)
+	}
+	t.ColDefs[col].Fdecimals = scale
+	t.ColDefs[col].Currency = symbol
+	t.ColDefs[col].Rounding = rounding
+	return nil
+}
+
+// Putm updates the Cell at row,col with the Decimal value v and sets its type
+// to CELLDECIMAL. v is rounded to the scale of the column. If row < 0 then row
+// is set to the last row of the table. If row or col is out of bounds, or v
+// does not fit in an int64 at the scale of the column, the cell is not changed
+// and the return value is false. Otherwise, the return value is true.
+func (t *Table) Putm(row, col int, v Decimal) bool {
+	if row >= len(t.Row) || col >= len(t.ColDefs) {
+		return false
+	}
+	if row < 0 {
+		row = len(t.Row) - 1
+	}
+	return t.putm(row, col, v) == nil
+}
+
+// putm stores v in the cell at row,col, rounded to the scale of column col,
+// and widens the column if the formatted value does not fit. It returns an
+// error if v does not fit in an int64 at that scale.
+func (t *Table) putm(row, col int, v Decimal) error {
+	cd := &t.ColDefs[col]
+	m, err := v.rescale(cd.Fdecimals, cd.Rounding)
+	if err != nil {
+		return err
+	}
+	t.Row[row].Col[col].Type = CELLDECIMAL
+	t.Row[row].Col[col].Mval = m
+	if w := displayWidth(t.decimalString(col, &t.Row[row].Col[col])); w > cd.Width {
+		cd.Width = w
+		t.AdjustFormatString(cd)
+	}
+	return nil
+}
+
+// Getm returns the Decimal at the supplied row,col.  If the supplied
+// row or col is outside the table's boundaries, then 0 is returned
+func (t *Table) Getm(row, col int) Decimal {
+	if row >= len(t.Row) || col >= len(t.ColDefs) {
+		return Decimal{}
+	}
+	return t.Row[row].Col[col].Mval
+}
+
+// decimalString returns the decimal in c formatted for column col
+func (t *Table) decimalString(col int, c *Cell) string {
+	neg, whole, frac := c.Mval.digits()
+	return t.numberFormat(col).format(neg, whole, frac, t.ColDefs[col].Currency)
+}
@@ -0,0 +1,215 @@
+package gotable
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestDecimalRounding(t *testing.T) {
+	var m = []struct {
+		s      string
+		mode   int
+		expect string
+	}{
+		{"2.675", ROUNDHALFUP, "2.68"},
+		{"2.665", ROUNDHALFUP, "2.67"},
+		{"-2.665", ROUNDHALFUP, "-2.67"},
+		{"2.675", ROUNDHALFEVEN, "2.68"},
+		{"2.665", ROUNDHALFEVEN, "2.66"},
+		{"-2.665", ROUNDHALFEVEN, "-2.66"},
+		{"2.6651", ROUNDHALFEVEN, "2.67"},
+		{"2.679", ROUNDDOWN, "2.67"},
+		{"-2.679", ROUNDDOWN, "-2.67"},
+		{"2.671", ROUNDUP, "2.68"},
+		{"-2.671", ROUNDUP, "-2.68"},
+		{"0.005", ROUNDHALFUP, "0.01"},
+		{"7", ROUNDHALFUP, "7.00"},
+	}
+	for i := 0; i < len(m); i++ {
+		d, err := ParseDecimal(m[i].s)
+		if err != nil {
+			t.Errorf("decimal_test: ParseDecimal(%q): %s\n", m[i].s, err.Error())
+			continue
+		}
+		if s := d.Rescale(2, m[i].mode).String(); s != m[i].expect {
+			t.Errorf("decimal_test: %s mode %d: expected %s, found %s\n", m[i].s, m[i].mode, m[i].expect, s)
+		}
+	}
+	for _, s := range []string{"", "-", "1.2.3", "12a", "--1", "99999999999999999999"} {
+		if _, err := ParseDecimal(s); err == nil {
+			t.Errorf("decimal_test: expected an error parsing %q\n", s)
+		}
+	}
+	if d := DecimalFromFloat(1.005, 2, ROUNDHALFUP); d.String() != "1.01" {
+		t.Errorf("decimal_test: DecimalFromFloat: expected 1.01, found %s\n", d.String())
+	}
+}
+
+func TestDecimalColumn(t *testing.T) {
+	var tbl Table
+	tbl.Init()
+	tbl.AddColumn("Unit", 6, CELLSTRING, COLJUSTIFYLEFT)
+	tbl.AddColumn("Rent", 12, CELLDECIMAL, COLJUSTIFYRIGHT)
+	tbl.AddColumn("Float", 12, CELLFLOAT, COLJUSTIFYRIGHT)
+	if err := tbl.SetColDecimal(1, 2, "$", ROUNDHALFEVEN); err != nil {
+		t.Errorf("decimal_test: SetColDecimal: %s\n", err.Error())
+	}
+
+	// ten rows of 0.10 add up to exactly 1.00
+	for i := 0; i < 10; i++ {
+		tbl.AddRow()
+		tbl.Puts(-1, 0, "A")
+		tbl.Putm(-1, 1, NewDecimal(10, 2))
+		tbl.Putf(-1, 2, 0.1)
+	}
+	tbl.AddRow()
+	tbl.Puts(-1, 0, "B")
+	tbl.Putm(-1, 1, NewDecimal(-1234565, 3)) // rounds to even: -1234.56
+	tbl.Putf(-1, 2, 0)
+
+	if d := tbl.Getm(10, 1); d.String() != "-1234.56" {
+		t.Errorf("decimal_test: Putm: expected -1234.56, found %s\n", d.String())
+	}
+	sum := tbl.SumRows(1, 0, 9)
+	if sum.Type != CELLDECIMAL || sum.Mval.Cmp(NewDecimal(1, 0)) != 0 {
+		t.Errorf("decimal_test: SumRows: expected 1.00, found %#v\n", sum)
+	}
+	if c := tbl.AggregateRows(1, 0, 9, AggSum); c.Type != CELLDECIMAL || c.Mval.String() != "1.00" {
+		t.Errorf("decimal_test: AggSum: expected 1.00, found %#v\n", c)
+	}
+	if c := tbl.CountDistinct(1); c.Ival != 2 {
+		t.Errorf("decimal_test: CountDistinct: expected 2, found %d\n", c.Ival)
+	}
+
+	tbl.AddRow()
+	tbl.Put(-1, 1, tbl.Sum(1))
+	s, _ := tbl.SprintTable()
+	if !strings.Contains(s, "B         -$1,234.56") || !strings.Contains(s, "-$1,233.56") || !strings.Contains(s, "$0.10") {
+		t.Errorf("decimal_test: unexpected text output:\n%s\n", s)
+	}
+	var b bytes.Buffer
+	tbl.CSVprintTable(&b)
+	if !strings.Contains(b.String(), "-$1,234.56") {
+		t.Errorf("decimal_test: unexpected csv output:\n%s\n", b.String())
+	}
+
+	tbl.SortBy(0, len(tbl.Row)-1, SortKey{Col: 1})
+	if tbl.Gets(0, 0) != "B" {
+		t.Errorf("decimal_test: sort: expected B first, found %s\n", tbl.Gets(0, 0))
+	}
+}
+
+func TestDecimalOverflow(t *testing.T) {
+	// scaling up past the range of an int64 leaves the value unchanged
+	big := NewDecimal(math.MaxInt64/10, 0)
+	if d := big.Rescale(2, ROUNDHALFUP); d != big {
+		t.Errorf("decimal_test: Rescale: expected %s unchanged, found %s\n", big.String(), d.String())
+	}
+	if d := NewDecimal(1, 0).Rescale(19, ROUNDHALFUP); d.Scale != 0 {
+		t.Errorf("decimal_test: Rescale: expected scale 0, found %d\n", d.Scale)
+	}
+
+	// dropping more than 18 digits does not overflow
+	var m = []struct {
+		d      Decimal
+		mode   int
+		expect int64
+	}{
+		{NewDecimal(math.MaxInt64, 19), ROUNDHALFUP, 1},
+		{NewDecimal(math.MinInt64, 19), ROUNDHALFUP, -1},
+		{NewDecimal(5000000000000000000, 19), ROUNDHALFEVEN, 0},
+		{NewDecimal(12345, 25), ROUNDHALFUP, 0},
+		{NewDecimal(12345, 25), ROUNDUP, 1},
+		{NewDecimal(-12345, 25), ROUNDUP, -1},
+		{NewDecimal(-12345, 25), ROUNDDOWN, 0},
+	}
+	for i := 0; i < len(m); i++ {
+		if d := m[i].d.Rescale(0, m[i].mode); d.Units != m[i].expect || d.Scale != 0 {
+			t.Errorf("decimal_test: %d: Rescale(%s, 0): expected %d, found %s\n", i, m[i].d.String(), m[i].expect, d.String())
+		}
+	}
+
+	var tbl Table
+	tbl.Init()
+	tbl.AddColumn("Rent", 12, CELLDECIMAL, COLJUSTIFYRIGHT)
+	tbl.SetColDecimal(0, 4, "", ROUNDHALFUP)
+	tbl.AddRow()
+	tbl.Putm(-1, 0, NewDecimal(5, 0))
+	if tbl.Putm(-1, 0, NewDecimal(math.MaxInt64/100, 0)) {
+		t.Errorf("decimal_test: Putm: expected false for a value that does not fit\n")
+	}
+	if d := tbl.Getm(0, 0); d.String() != "5.0000" {
+		t.Errorf("decimal_test: Putm: expected the cell to keep 5.0000, found %s\n", d.String())
+	}
+
+	type rent struct {
+		Amount Decimal `gotable:"decimals=4"`
+	}
+	if _, err := FromStructs([]rent{{NewDecimal(math.MaxInt64/100, 0)}}, nil); err == nil {
+		t.Errorf("decimal_test: FromStructs: expected an error for a value that does not fit\n")
+	}
+}
+
+func TestDecimalArithmeticOverflow(t *testing.T) {
+	big := NewDecimal(9e18, 0)
+	if d, err := big.Add(NewDecimal(1, 2)); err == nil {
+		t.Errorf("decimal_test: Add: expected an error aligning the scales, found %s\n", d.String())
+	}
+	if d, err := big.Add(big); err == nil {
+		t.Errorf("decimal_test: Add: expected an error for the sum, found %s\n", d.String())
+	}
+	if d, err := NewDecimal(-9e18, 0).Sub(big); err == nil {
+		t.Errorf("decimal_test: Sub: expected an error, found %s\n", d.String())
+	}
+	if d, err := NewDecimal(1050, 2).Sub(NewDecimal(1, 0)); err != nil || d.String() != "9.50" {
+		t.Errorf("decimal_test: Sub: expected 9.50, found %s %v\n", d.String(), err)
+	}
+	var m = []struct {
+		a, b   Decimal
+		expect int
+	}{
+		{big, NewDecimal(1, 2), 1},
+		{NewDecimal(1, 2), big, -1},
+		{NewDecimal(-9e18, 0), NewDecimal(1, 2), -1},
+		{NewDecimal(1, 2), NewDecimal(-9e18, 0), 1},
+		{NewDecimal(100, 2), NewDecimal(1, 0), 0},
+	}
+	for i := 0; i < len(m); i++ {
+		if r := m[i].a.Cmp(m[i].b); r != m[i].expect {
+			t.Errorf("decimal_test: Cmp(%s, %s): expected %d, found %d\n", m[i].a.String(), m[i].b.String(), m[i].expect, r)
+		}
+	}
+
+	// sums that do not fit in a Decimal are done in floats
+	c := AggSum([]Cell{{Type: CELLDECIMAL, Mval: big}, {Type: CELLDECIMAL, Mval: big}})
+	if c.Type != CELLFLOAT || c.Fval != 1.8e19 {
+		t.Errorf("decimal_test: AggSum: expected a float 1.8e19, found %#v\n", c)
+	}
+}
+
+func TestDecimalWidth(t *testing.T) {
+	var tbl Table
+	tbl.Init()
+	tbl.AddColumn("Rent", 8, CELLDECIMAL, COLJUSTIFYRIGHT)
+	tbl.SetColDecimal(0, 2, "$", ROUNDHALFUP)
+	tbl.AddRow()
+	tbl.Putm(-1, 0, NewDecimal(123456789, 2))
+	if tbl.ColDefs[0].Width != 13 {
+		t.Errorf("decimal_test: expected Putm to widen the column to 13, found %d\n", tbl.ColDefs[0].Width)
+	}
+
+	// a total put with Put is not cut either
+	tbl.AddRow()
+	tbl.Put(-1, 0, Cell{Type: CELLDECIMAL, Mval: NewDecimal(12345678900, 2)})
+	s, _ := tbl.SprintTable()
+	if !strings.Contains(s, "$1,234,567.89\n") || !strings.Contains(s, "$123,456,789.00\n") {
+		t.Errorf("decimal_test: unexpected text output:\n%s\n", s)
+	}
+	var b bytes.Buffer
+	tbl.CSVprintTable(&b)
+	if !strings.Contains(b.String(), "$1,234,567.89") || !strings.Contains(b.String(), "$123,456,789.00") {
+		t.Errorf("decimal_test: unexpected csv output:\n%s\n", b.String())
+	}
+}
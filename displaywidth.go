@@ -0,0 +1,156 @@
+package gotable
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Text output is laid out in terminal columns. East Asian wide characters and
+// most emoji take two columns, combining marks and joiners take none, and a
+// user perceived character (grapheme cluster) such as "e" + U+0301 or a flag
+// made of two regional indicators must never be cut in half.
+
+// wideRanges are the East Asian Wide and Fullwidth ranges, including the
+// emoji that are shown with emoji presentation by default
+var wideRanges = [][2]rune{
+	{0x1100, 0x115f}, {0x231a, 0x231b}, {0x2329, 0x232a}, {0x23e9, 0x23ec}, {0x23f0, 0x23f0},
+	{0x23f3, 0x23f3}, {0x25fd, 0x25fe}, {0x2614, 0x2615}, {0x2648, 0x2653}, {0x267f, 0x267f},
+	{0x2693, 0x2693}, {0x26a1, 0x26a1}, {0x26aa, 0x26ab}, {0x26bd, 0x26be}, {0x26c4, 0x26c5},
+	{0x26ce, 0x26ce}, {0x26d4, 0x26d4}, {0x26ea, 0x26ea}, {0x26f2, 0x26f3}, {0x26f5, 0x26f5},
+	{0x26fa, 0x26fa}, {0x26fd, 0x26fd}, {0x2705, 0x2705}, {0x270a, 0x270b}, {0x2728, 0x2728},
+	{0x274c, 0x274c}, {0x274e, 0x274e}, {0x2753, 0x2755}, {0x2757, 0x2757}, {0x2795, 0x2797},
+	{0x27b0, 0x27b0}, {0x27bf, 0x27bf}, {0x2b1b, 0x2b1c}, {0x2b50, 0x2b50}, {0x2b55, 0x2b55},
+	{0x2e80, 0x303e}, {0x3041, 0x33ff}, {0x3400, 0x4dbf}, {0x4e00, 0x9fff}, {0xa000, 0xa4cf},
+	{0xa960, 0xa97f}, {0xac00, 0xd7a3}, {0xf900, 0xfaff}, {0xfe10, 0xfe19}, {0xfe30, 0xfe6f},
+	{0xff00, 0xff60}, {0xffe0, 0xffe6}, {0x16fe0, 0x16fe4}, {0x17000, 0x18aff}, {0x1b000, 0x1b2ff},
+	{0x1f004, 0x1f004}, {0x1f0cf, 0x1f0cf}, {0x1f18e, 0x1f18e}, {0x1f191, 0x1f19a}, {0x1f200, 0x1f202},
+	{0x1f210, 0x1f23b}, {0x1f240, 0x1f248}, {0x1f250, 0x1f251}, {0x1f260, 0x1f265}, {0x1f300, 0x1f320},
+	{0x1f32d, 0x1f335}, {0x1f337, 0x1f37c}, {0x1f37e, 0x1f393}, {0x1f3a0, 0x1f3ca}, {0x1f3cf, 0x1f3d3},
+	{0x1f3e0, 0x1f3f0}, {0x1f3f4, 0x1f3f4}, {0x1f3f8, 0x1f43e}, {0x1f440, 0x1f440}, {0x1f442, 0x1f4fc},
+	{0x1f4ff, 0x1f53d}, {0x1f54b, 0x1f54e}, {0x1f550, 0x1f567}, {0x1f57a, 0x1f57a}, {0x1f595, 0x1f596},
+	{0x1f5a4, 0x1f5a4}, {0x1f5fb, 0x1f64f}, {0x1f680, 0x1f6c5}, {0x1f6cc, 0x1f6cc}, {0x1f6d0, 0x1f6d2},
+	{0x1f6d5, 0x1f6d7}, {0x1f6eb, 0x1f6ec}, {0x1f6f4, 0x1f6fc}, {0x1f7e0, 0x1f7eb}, {0x1f90c, 0x1f93a},
+	{0x1f93c, 0x1f945}, {0x1f947, 0x1f9ff}, {0x1fa70, 0x1faff}, {0x20000, 0x2fffd}, {0x30000, 0x3fffd},
+}
+
+// isWideRune reports whether r takes two terminal columns
+func isWideRune(r rune) bool {
+	lo, hi := 0, len(wideRanges)-1
+	for lo <= hi {
+		m := (lo + hi) / 2
+		switch {
+		case r < wideRanges[m][0]:
+			hi = m - 1
+		case r > wideRanges[m][1]:
+			lo = m + 1
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+// isRegionalIndicator reports whether r is one of the letters used in pairs
+// to write flags
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1f1e6 && r <= 0x1f1ff
+}
+
+// isClusterExtender reports whether r belongs to the grapheme cluster of the
+// rune before it
+func isClusterExtender(r rune) bool {
+	switch {
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc):
+		return true // combining marks and variation selectors
+	case r == 0x200c || r == 0x200d: // zero width non-joiner and joiner
+		return true
+	case r >= 0x1f3fb && r <= 0x1f3ff: // emoji skin tone modifiers
+		return true
+	case r >= 0xe0020 && r <= 0xe007f: // tags used by subdivision flags
+		return true
+	case r >= 0x1160 && r <= 0x11ff: // hangul jamo vowels and final consonants
+		return true
+	}
+	return false
+}
+
+// runeWidth returns the number of terminal columns taken by r on its own
+func runeWidth(r rune) int {
+	switch {
+	case r < 0x20 || (r >= 0x7f && r < 0xa0):
+		return 0
+	case isClusterExtender(r) || unicode.Is(unicode.Cf, r):
+		return 0
+	case isWideRune(r):
+		return 2
+	}
+	return 1
+}
+
+// nextCluster returns the length in runes of the grapheme cluster at the start
+// of r and the number of terminal columns it takes
+func nextCluster(r []rune) (int, int) {
+	if len(r) == 0 {
+		return 0, 0
+	}
+	n, w := 1, runeWidth(r[0])
+	if isRegionalIndicator(r[0]) && len(r) > 1 && isRegionalIndicator(r[1]) {
+		return 2, 2
+	}
+	for n < len(r) && isClusterExtender(r[n]) {
+		switch {
+		case r[n] == 0x200d && n+1 < len(r):
+			n++ // the joined rune is shown as part of the same glyph
+		case r[n] == 0xfe0f && w == 1:
+			w = 2 // emoji presentation
+		}
+		n++
+	}
+	return n, w
+}
+
+// displayWidth returns the number of terminal columns needed to show s
+func displayWidth(s string) int {
+	r := []rune(s)
+	w := 0
+	for len(r) > 0 {
+		n, cw := nextCluster(r)
+		w += cw
+		r = r[n:]
+	}
+	return w
+}
+
+// truncateToWidth returns the longest prefix of s that fits in width terminal
+// columns. It never cuts a grapheme cluster.
+func truncateToWidth(s string, width int) string {
+	r := []rune(s)
+	i, w := 0, 0
+	for i < len(r) {
+		n, cw := nextCluster(r[i:])
+		if w+cw > width {
+			break
+		}
+		w += cw
+		i += n
+	}
+	if i == len(r) {
+		return s
+	}
+	return string(r[:i])
+}
+
+// padToWidth returns s truncated to width terminal columns and padded with
+// spaces to exactly width columns, on the right if left is true, otherwise on
+// the left. It is the display width aware version of "%-*.*s" and "%*.*s".
+func padToWidth(s string, width int, left bool) string {
+	s = truncateToWidth(s, width)
+	pad := width - displayWidth(s)
+	if pad <= 0 {
+		return s
+	}
+	if left {
+		return s + strings.Repeat(" ", pad)
+	}
+	return strings.Repeat(" ", pad) + s
+}
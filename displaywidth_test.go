@@ -0,0 +1,72 @@
+package gotable
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDisplayWidth(t *testing.T) {
+	widths := []struct {
+		s string
+		w int
+	}{
+		{"abc", 3},
+		{"M\u00fcller", 6},
+		{"Mu\u0308ller", 6},             // u + combining diaeresis
+		{"\u65e5\u672c\u8a9e", 6},       // wide
+		{"\uff76\uff80\uff76\uff85", 4}, // halfwidth katakana
+		{"\U0001F600", 2},               // emoji
+		{"\u2764\ufe0f", 2},             // heart with emoji presentation
+		{"\U0001F468\u200d\U0001F469\u200d\U0001F467", 2}, // family, joined with zero width joiners
+		{"\U0001F44D\U0001F3FD", 2},                       // skin tone modifier
+		{"\U0001F1EF\U0001F1F5", 2},                       // flag
+		{"a\u200bb", 2},                                   // zero width space
+	}
+	for i := 0; i < len(widths); i++ {
+		if w := displayWidth(widths[i].s); w != widths[i].w {
+			t.Errorf("displaywidth_test: displayWidth(%q): expected %d, found %d\n", widths[i].s, widths[i].w, w)
+		}
+	}
+
+	if s := truncateToWidth("日本語", 5); s != "日本" {
+		t.Errorf("displaywidth_test: truncateToWidth: found %q\n", s)
+	}
+	if s := truncateToWidth("ééé", 2); s != "éé" {
+		t.Errorf("displaywidth_test: truncateToWidth should keep combining marks: found %q\n", s)
+	}
+	if s := padToWidth("日本語", 5, true); s != "日本 " {
+		t.Errorf("displaywidth_test: padToWidth left: found %q\n", s)
+	}
+	if s := padToWidth("🇯🇵", 4, false); s != "  🇯🇵" {
+		t.Errorf("displaywidth_test: padToWidth right: found %q\n", s)
+	}
+
+	// every line of the text output has the same width
+	var tbl Table
+	tbl.Init()
+	tbl.AddColumn("Tenant", 8, CELLSTRING, COLJUSTIFYLEFT)
+	tbl.AddColumn("Rent", 8, CELLFLOAT, COLJUSTIFYRIGHT)
+	for _, name := range []string{"Smith", "Zoë", "Zoë", "山田太郎", "東京都港区 六本木", "👨‍👩‍👧 family", "🇯🇵 Tanaka"} {
+		tbl.AddRow()
+		tbl.Puts(-1, 0, name)
+		tbl.Putf(-1, 1, 1250)
+	}
+	s, _ := tbl.SprintTable()
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	width := tbl.ColDefs[0].Width + 2 + tbl.ColDefs[1].Width
+	for i := 0; i < len(lines); i++ {
+		if w := displayWidth(lines[i]); w != width {
+			t.Errorf("displaywidth_test: line %d is %d columns wide, expected %d:\n%s\n", i, w, width, s)
+		}
+	}
+	if tbl.ColDefs[0].Width != 10 || !strings.Contains(s, "\n六本木    ") {
+		t.Errorf("displaywidth_test: expected the long name to wrap:\n%s\n", s)
+	}
+
+	// TightenColumns measures display width
+	tbl.ColDefs[0].Width = 20
+	tbl.TightenColumns()
+	if tbl.ColDefs[0].Width != 17 {
+		t.Errorf("displaywidth_test: TightenColumns: expected width 17, found %d\n", tbl.ColDefs[0].Width)
+	}
+}
@@ -0,0 +1,211 @@
+package gotable
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Style is the emphasis given to the cells matched by a FormatRule. Colors
+// are written as "#rrggbb". HTML and pdf output get the style as css, text
+// output as ANSI escape sequences and xlsx output as a font and fill.
+type Style struct {
+	Bold       bool
+	Italic     bool
+	Underline  bool
+	Color      string // text color, "" for the default
+	Background string // background color, "" for none
+}
+
+// FormatRule gives Style to the cells of column Col for which Match returns
+// true. Col is -1 for a rule that applies to every column. Rules are checked
+// when the table is printed, so they follow the values through sorting and
+// edits. Match is not called for empty or null cells.
+type FormatRule struct {
+	Col   int
+	Match func(c *Cell) bool
+	Style Style
+}
+
+// AddFormatRule adds a rule that gives style s to every cell of the table for
+// which match returns true. When several rules match a cell their styles are
+// combined and the colors of the rule added last win.
+func (t *Table) AddFormatRule(match func(c *Cell) bool, s Style) error {
+	return t.addFormatRule("AddFormatRule", -1, match, s)
+}
+
+// AddColFormatRule adds a rule that gives style s to the cells of column col
+// for which match returns true, for example
+//
+//	t.AddColFormatRule(3, func(c *Cell) bool { return c.Type == CELLFLOAT && c.Fval < 0 }, Style{Color: "#cc0000"})
+func (t *Table) AddColFormatRule(col int, match func(c *Cell) bool, s Style) error {
+	if err := t.HasValidColumn(col); err != nil {
+		return err
+	}
+	return t.addFormatRule("AddColFormatRule", col, match, s)
+}
+
+// ClearFormatRules removes all format rules of the table
+func (t *Table) ClearFormatRules() {
+	t.formatRules = nil
+}
+
+func (t *Table) addFormatRule(funcname string, col int, match func(c *Cell) bool, s Style) error {
+	if match == nil {
+		return fmt.Errorf("%s: match is nil", funcname)
+	}
+	colors := []string{s.Color, s.Background}
+	for i := 0; i < len(colors); i++ {
+		if _, _, _, ok := parseColor(colors[i]); colors[i] != "" && !ok {
+			return fmt.Errorf("%s: invalid color %q, expected #rrggbb", funcname, colors[i])
+		}
+	}
+	t.formatRules = append(t.formatRules, FormatRule{Col: col, Match: match, Style: s})
+	return nil
+}
+
+// SetTextANSI sets whether the styles of the format rules are shown in text
+// output using ANSI escape sequences. They are off by default, so text output
+// stays plain when it is written to a file or a pipe; turn them on for output
+// meant for a terminal.
+func (t *Table) SetTextANSI(enabled bool) {
+	t.textANSI = enabled
+}
+
+// styleCells returns the cells of a line of row with the styles of the format
+// rules applied. The cells are already padded to the width of their columns.
+func (tt *TextTable) styleCells(row int, cells []string) []string {
+	if tt.Plain || len(tt.Table.formatRules) == 0 {
+		return cells
+	}
+	styled := make([]string, len(cells))
+	for i := 0; i < len(cells); i++ {
+		styled[i] = cells[i]
+		if st := tt.Table.ruleStyle(row, i); st != nil {
+			styled[i] = st.ansi(cells[i])
+		}
+	}
+	return styled
+}
+
+// ruleStyle returns the combined style of the format rules that match the
+// cell at row,col, or nil if no rule matches
+func (t *Table) ruleStyle(row, col int) *Style {
+	c := &t.Row[row].Col[col]
+	if isNullCell(c) {
+		return nil
+	}
+	var st *Style
+	for i := 0; i < len(t.formatRules); i++ {
+		r := &t.formatRules[i]
+		if (r.Col >= 0 && r.Col != col) || !r.Match(c) {
+			continue
+		}
+		if st == nil {
+			st = &Style{}
+		}
+		st.Bold = st.Bold || r.Style.Bold
+		st.Italic = st.Italic || r.Style.Italic
+		st.Underline = st.Underline || r.Style.Underline
+		if r.Style.Color != "" {
+			st.Color = r.Style.Color
+		}
+		if r.Style.Background != "" {
+			st.Background = r.Style.Background
+		}
+	}
+	return st
+}
+
+// parseColor returns the red, green and blue parts of a "#rrggbb" color
+func parseColor(s string) (uint8, uint8, uint8, bool) {
+	if len(s) != 7 || s[0] != '#' {
+		return 0, 0, 0, false
+	}
+	v, err := strconv.ParseUint(s[1:], 16, 32)
+	if err != nil {
+		return 0, 0, 0, false
+	}
+	return uint8(v >> 16), uint8(v >> 8), uint8(v), true
+}
+
+// css returns the css properties for s
+func (s *Style) css() []*CSSProperty {
+	var props []*CSSProperty
+	if s.Background != "" {
+		props = append(props, &CSSProperty{Name: "background-color", Value: s.Background})
+	}
+	if s.Color != "" {
+		props = append(props, &CSSProperty{Name: "color", Value: s.Color})
+	}
+	if s.Italic {
+		props = append(props, &CSSProperty{Name: "font-style", Value: "italic"})
+	}
+	if s.Bold {
+		props = append(props, &CSSProperty{Name: "font-weight", Value: "bold"})
+	}
+	if s.Underline {
+		props = append(props, &CSSProperty{Name: "text-decoration", Value: "underline"})
+	}
+	return props
+}
+
+// mergeCSSProperties returns the properties in a and b sorted by name. The
+// properties in b replace those in a with the same name.
+func mergeCSSProperties(a, b []*CSSProperty) []*CSSProperty {
+	m := map[string]*CSSProperty{}
+	for i := 0; i < len(a); i++ {
+		m[a[i].Name] = a[i]
+	}
+	for i := 0; i < len(b); i++ {
+		m[b[i].Name] = b[i]
+	}
+	props := make([]*CSSProperty, 0, len(m))
+	for _, p := range m {
+		props = append(props, p)
+	}
+	sort.Slice(props, func(i, j int) bool { return props[i].Name < props[j].Name })
+	return props
+}
+
+// ansi returns text wrapped in the ANSI escape sequences for s. Colors use
+// 24-bit color sequences.
+func (s *Style) ansi(text string) string {
+	var codes []string
+	if s.Bold {
+		codes = append(codes, "1")
+	}
+	if s.Italic {
+		codes = append(codes, "3")
+	}
+	if s.Underline {
+		codes = append(codes, "4")
+	}
+	if r, g, b, ok := parseColor(s.Color); ok {
+		codes = append(codes, fmt.Sprintf("38;2;%d;%d;%d", r, g, b))
+	}
+	if r, g, b, ok := parseColor(s.Background); ok {
+		codes = append(codes, fmt.Sprintf("48;2;%d;%d;%d", r, g, b))
+	}
+	if len(codes) == 0 {
+		return text
+	}
+	return "\x1b[" + strings.Join(codes, ";") + "m" + text + "\x1b[0m"
+}
+
+// xlsxColor returns the "#rrggbb" color c as the RRGGBB used in xlsx files
+func xlsxColor(c string) string {
+	return strings.ToUpper(strings.TrimPrefix(c, "#"))
+}
+
+// pdfColor returns the pdf operands for the "#rrggbb" color c, either the
+// red, green and blue parts or, if gray is true, the gray level with the same
+// luminance
+func pdfColor(c string, gray bool) string {
+	r, g, b, _ := parseColor(c)
+	if gray {
+		return fmt.Sprintf("%.3f g", (0.299*float64(r)+0.587*float64(g)+0.114*float64(b))/255)
+	}
+	return fmt.Sprintf("%.3f %.3f %.3f rg", float64(r)/255, float64(g)/255, float64(b)/255)
+}
@@ -0,0 +1,144 @@
+package gotable
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatRules(t *testing.T) {
+	var tbl Table
+	tbl.Init()
+	tbl.AddColumn("Tenant", 8, CELLSTRING, COLJUSTIFYLEFT)
+	tbl.AddColumn("Balance", 10, CELLFLOAT, COLJUSTIFYRIGHT)
+	balances := []float64{120, -45.5, 0}
+	for i := 0; i < len(balances); i++ {
+		tbl.AddRow()
+		tbl.Puts(-1, 0, []string{"Smith", "Jones", "Brown"}[i])
+		tbl.Putf(-1, 1, balances[i])
+	}
+
+	negative := func(c *Cell) bool { return c.Type == CELLFLOAT && c.Fval < 0 }
+	if err := tbl.AddColFormatRule(1, negative, Style{Color: "#cc0000", Bold: true}); err != nil {
+		t.Errorf("formatrule_test: AddColFormatRule: %s\n", err.Error())
+	}
+	if err := tbl.AddFormatRule(func(c *Cell) bool { return c.Type == CELLSTRING && c.Sval == "Jones" }, Style{Background: "#ffff00"}); err != nil {
+		t.Errorf("formatrule_test: AddFormatRule: %s\n", err.Error())
+	}
+	if err := tbl.AddColFormatRule(5, negative, Style{}); err == nil {
+		t.Errorf("formatrule_test: expected an error for an invalid column\n")
+	}
+	if err := tbl.AddFormatRule(negative, Style{Color: "red"}); err == nil {
+		t.Errorf("formatrule_test: expected an error for an invalid color\n")
+	}
+	if err := tbl.AddFormatRule(nil, Style{}); err == nil {
+		t.Errorf("formatrule_test: expected an error for a nil match\n")
+	}
+
+	// rules are checked when the table is printed, so they follow sorting
+	tbl.Sort(0, tbl.RowCount()-1, 1)
+	if tbl.Gets(0, 0) != "Jones" {
+		t.Errorf("formatrule_test: expected Jones first after sorting, found %s\n", tbl.Gets(0, 0))
+	}
+	if st := tbl.ruleStyle(0, 1); st == nil || st.Color != "#cc0000" || !st.Bold {
+		t.Errorf("formatrule_test: expected the negative balance to be styled, found %v\n", st)
+	}
+	if st := tbl.ruleStyle(1, 1); st != nil {
+		t.Errorf("formatrule_test: expected no style for a zero balance, found %v\n", st)
+	}
+
+	// text output is plain unless ANSI escape sequences are turned on
+	s, _ := tbl.SprintTable()
+	if strings.Contains(s, "\x1b") {
+		t.Errorf("formatrule_test: expected no escape sequences by default:\n%q\n", s)
+	}
+	tbl.SetTextANSI(true)
+	s, _ = tbl.SprintTable()
+	if !strings.Contains(s, "\x1b[48;2;255;255;0mJones   \x1b[0m  \x1b[1;38;2;204;0;0m    -45.50\x1b[0m\n") {
+		t.Errorf("formatrule_test: unexpected text output:\n%q\n", s)
+	}
+	if strings.Count(s, "\x1b[0m") != 2 {
+		t.Errorf("formatrule_test: expected only two styled cells:\n%q\n", s)
+	}
+	tbl.SetTextANSI(false)
+	if s, _ = tbl.SprintTable(); strings.Contains(s, "\x1b") {
+		t.Errorf("formatrule_test: expected no escape sequences:\n%q\n", s)
+	}
+
+	// an explicit cell css wins over the css of the rules
+	tbl.SetCellCSS(0, 1, []*CSSProperty{{Name: "color", Value: "blue"}})
+	var b bytes.Buffer
+	tbl.HTMLprintTable(&b)
+	if !strings.Contains(b.String(), ".cell-row-0-col-1{color:blue;font-weight:bold;text-align:right;}") ||
+		!strings.Contains(b.String(), ".cell-row-0-col-0{background-color:#ffff00;text-align:left;}") {
+		t.Errorf("formatrule_test: unexpected html output:\n%s\n", b.String())
+	}
+
+	b.Reset()
+	if err := tbl.XLSXprintTable(&b); err != nil {
+		t.Errorf("formatrule_test: XLSXprintTable: %s\n", err.Error())
+	}
+	styles := xlsxFiles(t, b.Bytes())["xl/styles.xml"]
+	if !strings.Contains(styles, `<font><b/><sz val="11"/><color rgb="FFCC0000"/>`) || !strings.Contains(styles, `<fgColor rgb="FFFFFF00"/>`) {
+		t.Errorf("formatrule_test: unexpected styles:\n%s\n", styles)
+	}
+
+	tbl.SetPDFRenderer(&NativePDFRenderer{})
+	for _, gray := range []bool{false, true} {
+		b.Reset()
+		if err := tbl.PDFprintTableOptions(context.Background(), &b, &PDFOptions{Grayscale: gray}); err != nil {
+			t.Errorf("formatrule_test: PDFprintTableOptions: %s\n", err.Error())
+			continue
+		}
+		checkPDFXref(t, b.Bytes())
+		expect := "0.800 0.000 0.000 rg"
+		if gray {
+			expect = "0.239 g"
+		}
+		if !strings.Contains(b.String(), expect) || !strings.Contains(b.String(), "BT /F2") || !strings.Contains(b.String(), "/Helvetica-BoldOblique") {
+			t.Errorf("formatrule_test: grayscale %v: expected %q in the pdf\n", gray, expect)
+		}
+	}
+
+	tbl.ClearFormatRules()
+	if st := tbl.ruleStyle(0, 1); st != nil {
+		t.Errorf("formatrule_test: expected no style after ClearFormatRules\n")
+	}
+}
+
+func TestFormatRuleColumns(t *testing.T) {
+	var tbl Table
+	tbl.Init()
+	tbl.AddColumn("Tenant", 8, CELLSTRING, COLJUSTIFYLEFT)
+	tbl.AddColumn("Due", 10, CELLDATE, COLJUSTIFYLEFT)
+	tbl.AddColumn("Balance", 10, CELLFLOAT, COLJUSTIFYRIGHT)
+	tbl.AddRow()
+	tbl.Puts(-1, 0, "Smith")
+	tbl.PutNull(-1, 1)
+	tbl.Putf(-1, 2, -10)
+
+	// a null date has a zero Dval, which must not count as overdue
+	now := time.Date(2020, time.March, 10, 0, 0, 0, 0, time.UTC)
+	overdue := func(c *Cell) bool { return c.Dval.Before(now) }
+	tbl.AddColFormatRule(1, overdue, Style{Bold: true})
+	tbl.AddColFormatRule(2, func(c *Cell) bool { return c.Fval < 0 }, Style{Color: "#cc0000"})
+	if st := tbl.ruleStyle(0, 1); st != nil {
+		t.Errorf("formatrule_test: expected no style for a null cell, found %v\n", st)
+	}
+
+	// rules follow their columns
+	tbl.InsertColumn(0, "Unit", 6, CELLSTRING, COLJUSTIFYLEFT)
+	tbl.MoveColumn(3, 1)
+	if st := tbl.ruleStyle(0, 1); st == nil || st.Color != "#cc0000" {
+		t.Errorf("formatrule_test: expected the balance rule to move with its column, found %v\n", st)
+	}
+	if st := tbl.ruleStyle(0, 3); st != nil {
+		t.Errorf("formatrule_test: expected no style in the old balance column, found %v\n", st)
+	}
+	tbl.DeleteColumn(1)
+	if len(tbl.formatRules) != 1 || tbl.formatRules[0].Col != 2 {
+		t.Errorf("formatrule_test: expected only the date rule in column 2, found %#v\n", tbl.formatRules)
+	}
+}
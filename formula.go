@@ -0,0 +1,386 @@
+package gotable
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// FormulaFunc computes the value of the cell at row in a computed column. It
+// usually works from the other cells of the same row.
+type FormulaFunc func(t *Table, row int) Cell
+
+// SetColFormula makes col a computed column. The cells of the column are set by
+// calling f for every row each time the table is printed, or when Recompute is
+// called. If col is a numeric column and f returns a number of another type,
+// the number is converted to the column's type. Pass a nil f to make col a
+// regular column again.
+func (t *Table) SetColFormula(col int, f FormulaFunc) error {
+	if err := t.HasValidColumn(col); err != nil {
+		return err
+	}
+	t.ColDefs[col].Formula = f
+	return nil
+}
+
+// SetColExpr makes col a computed column whose value is given by the
+// expression expr. An expression can use numbers, the operators + - * / and
+// parentheses, and the following:
+//
+//	[Title]       the value of the cell in the column with that title in the same row
+//	Sum([Title])  the sum of all the cells in that column. Also: Avg, Min, Max, Count
+//	Abs(x)        the absolute value of x
+//	Round(x, n)   x rounded to n decimal places
+//
+// For example: "[Debit] - [Credit]" or "[Amount] / Sum([Amount])". Columns are
+// found by title each time the expression is evaluated, so they can be moved
+// freely. If a cell used by the expression is null or not a number, or if it
+// divides by zero, the computed cell is null. col must be a CELLINT, CELLFLOAT
+// or CELLDECIMAL column; the result is rounded to fit the column. The total
+// rows added by GroupBy are left out of Sum and the other aggregates, and are
+// not computed.
+// Computed columns are computed from left to right.
+func (t *Table) SetColExpr(col int, expr string) error {
+	if err := t.HasValidColumn(col); err != nil {
+		return err
+	}
+	celltype := t.ColDefs[col].CellType
+	if celltype != CELLINT && celltype != CELLFLOAT && celltype != CELLDECIMAL {
+		return fmt.Errorf("SetColExpr: column %d is not a numeric column", col)
+	}
+	p := exprParser{s: expr}
+	n, err := p.parse()
+	if err != nil {
+		return err
+	}
+	for _, title := range n.titles(nil) {
+		if t.colIndex(title) < 0 {
+			return fmt.Errorf("SetColExpr: no column with title %q", title)
+		}
+	}
+	t.ColDefs[col].Formula = func(t *Table, row int) Cell {
+		var c Cell
+		v, ok := n.eval(t, row)
+		if !ok || math.IsInf(v, 0) || math.IsNaN(v) {
+			c.Type = CELLNULL
+			return c
+		}
+		c.Type = CELLFLOAT
+		c.Fval = v
+		return c
+	}
+	return nil
+}
+
+// Recompute sets the cells of all computed columns. The print methods call it,
+// so it only needs to be called to read computed values with Get and friends.
+func (t *Table) Recompute() {
+	t.aggCache = nil
+	for col := 0; col < len(t.ColDefs); col++ {
+		f := t.ColDefs[col].Formula
+		if f == nil {
+			continue
+		}
+		for row := 0; row < len(t.Row); row++ {
+			if t.Row[row].total {
+				continue // GroupBy computed it from the data rows
+			}
+			c := f(t, row)
+			t.Row[row].Col[col] = t.convertNumber(col, &c)
+		}
+		t.aggCache = nil // the column's values have changed
+	}
+}
+
+// dataRows returns the indeces of the rows that are not total rows added by
+// GroupBy
+func (t *Table) dataRows() []int {
+	var r []int
+	for i := 0; i < len(t.Row); i++ {
+		if !t.Row[i].total {
+			r = append(r, i)
+		}
+	}
+	return r
+}
+
+// convertNumber returns the number in c converted to the type of column col,
+// if col is a numeric column. Otherwise c is returned unchanged.
+func (t *Table) convertNumber(col int, c *Cell) Cell {
+	cd := &t.ColDefs[col]
+	v, ok := cellFloat(c)
+	if !ok || c.Type == cd.CellType {
+		return *c
+	}
+	switch cd.CellType {
+	case CELLINT:
+		if c.Type == CELLDECIMAL {
+			return Cell{Type: CELLINT, Ival: c.Mval.Rescale(0, cd.Rounding).Units}
+		}
+		return Cell{Type: CELLINT, Ival: int64(math.Round(v))}
+	case CELLFLOAT:
+		return Cell{Type: CELLFLOAT, Fval: v}
+	case CELLDECIMAL:
+		if c.Type == CELLINT {
+			return Cell{Type: CELLDECIMAL, Mval: Decimal{Units: c.Ival}.Rescale(cd.Fdecimals, cd.Rounding)}
+		}
+		return Cell{Type: CELLDECIMAL, Mval: DecimalFromFloat(v, cd.Fdecimals, cd.Rounding)}
+	}
+	return *c
+}
+
+// colIndex returns the index of the column with the supplied title, or -1
+func (t *Table) colIndex(title string) int {
+	for i := 0; i < len(t.ColDefs); i++ {
+		if t.ColDefs[i].ColTitle == title {
+			return i
+		}
+	}
+	return -1
+}
+
+// exprNode is a node in the syntax tree of an expression
+type exprNode struct {
+	op    string      // "num", "col", "neg", "+", "-", "*", "/", or a function name
+	num   float64     // value of a "num" node
+	title string      // column title of a "col" node
+	args  []*exprNode // operands
+}
+
+// exprAggs are the column aggregates that can be used in an expression
+var exprAggs = map[string]AggFunc{
+	"sum":   AggSum,
+	"avg":   AggAvg,
+	"min":   AggMin,
+	"max":   AggMax,
+	"count": AggCount,
+}
+
+// titles appends the column titles used in the expression to a
+func (n *exprNode) titles(a []string) []string {
+	if n.op == "col" {
+		a = append(a, n.title)
+	}
+	for i := 0; i < len(n.args); i++ {
+		a = n.args[i].titles(a)
+	}
+	return a
+}
+
+// eval computes the value of the expression for the supplied row. The second
+// return value is false if there is no value.
+func (n *exprNode) eval(t *Table, row int) (float64, bool) {
+	switch n.op {
+	case "num":
+		return n.num, true
+	case "col":
+		col := t.colIndex(n.title)
+		if col < 0 {
+			return 0, false
+		}
+		return cellFloat(&t.Row[row].Col[col])
+	}
+
+	if f, ok := exprAggs[n.op]; ok {
+		col := t.colIndex(n.args[0].title)
+		if col < 0 {
+			return 0, false
+		}
+		key := n.op + ":" + strconv.Itoa(col)
+		c, ok := t.aggCache[key]
+		if !ok {
+			c = f(t.colCells(col, t.dataRows()))
+			if t.aggCache == nil {
+				t.aggCache = map[string]Cell{}
+			}
+			t.aggCache[key] = c
+		}
+		return cellFloat(&c)
+	}
+
+	var v []float64
+	for i := 0; i < len(n.args); i++ {
+		x, ok := n.args[i].eval(t, row)
+		if !ok {
+			return 0, false
+		}
+		v = append(v, x)
+	}
+	switch n.op {
+	case "neg":
+		return -v[0], true
+	case "+":
+		return v[0] + v[1], true
+	case "-":
+		return v[0] - v[1], true
+	case "*":
+		return v[0] * v[1], true
+	case "/":
+		if v[1] == 0 {
+			return 0, false
+		}
+		return v[0] / v[1], true
+	case "abs":
+		return math.Abs(v[0]), true
+	case "round":
+		p := math.Pow(10, math.Round(v[1]))
+		return math.Round(v[0]*p) / p, true
+	}
+	return 0, false
+}
+
+// exprParser is a recursive descent parser for expressions
+type exprParser struct {
+	s   string
+	pos int
+}
+
+func (p *exprParser) errorf(format string, a ...interface{}) error {
+	return fmt.Errorf("expression %q, position %d: %s", p.s, p.pos, fmt.Sprintf(format, a...))
+}
+
+// peek skips white space and returns the next character, or 0 at the end
+func (p *exprParser) peek() byte {
+	for p.pos < len(p.s) && (p.s[p.pos] == ' ' || p.s[p.pos] == '\t') {
+		p.pos++
+	}
+	if p.pos < len(p.s) {
+		return p.s[p.pos]
+	}
+	return 0
+}
+
+func (p *exprParser) parse() (*exprNode, error) {
+	n, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek() != 0 {
+		return nil, p.errorf("unexpected %q", p.s[p.pos])
+	}
+	return n, nil
+}
+
+// parseSum parses: term { (+|-) term }
+func (p *exprParser) parseSum() (*exprNode, error) {
+	n, err := p.parseTerm()
+	for err == nil && (p.peek() == '+' || p.peek() == '-') {
+		op := string(p.s[p.pos])
+		p.pos++
+		var r *exprNode
+		if r, err = p.parseTerm(); err == nil {
+			n = &exprNode{op: op, args: []*exprNode{n, r}}
+		}
+	}
+	return n, err
+}
+
+// parseTerm parses: unary { (*|/) unary }
+func (p *exprParser) parseTerm() (*exprNode, error) {
+	n, err := p.parseUnary()
+	for err == nil && (p.peek() == '*' || p.peek() == '/') {
+		op := string(p.s[p.pos])
+		p.pos++
+		var r *exprNode
+		if r, err = p.parseUnary(); err == nil {
+			n = &exprNode{op: op, args: []*exprNode{n, r}}
+		}
+	}
+	return n, err
+}
+
+// parseUnary parses: -unary | primary
+func (p *exprParser) parseUnary() (*exprNode, error) {
+	if p.peek() == '-' {
+		p.pos++
+		n, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &exprNode{op: "neg", args: []*exprNode{n}}, nil
+	}
+	return p.parsePrimary()
+}
+
+// parsePrimary parses: number | [title] | (sum) | name(args)
+func (p *exprParser) parsePrimary() (*exprNode, error) {
+	c := p.peek()
+	switch {
+	case c == 0:
+		return nil, p.errorf("unexpected end of expression")
+	case c == '(':
+		p.pos++
+		n, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ')' {
+			return nil, p.errorf("missing )")
+		}
+		p.pos++
+		return n, nil
+	case c == '[':
+		i := strings.IndexByte(p.s[p.pos:], ']')
+		if i < 0 {
+			return nil, p.errorf("missing ]")
+		}
+		n := &exprNode{op: "col", title: p.s[p.pos+1 : p.pos+i]}
+		p.pos += i + 1
+		return n, nil
+	case c == '.' || (c >= '0' && c <= '9'):
+		start := p.pos
+		for p.pos < len(p.s) && (p.s[p.pos] == '.' || (p.s[p.pos] >= '0' && p.s[p.pos] <= '9')) {
+			p.pos++
+		}
+		v, err := strconv.ParseFloat(p.s[start:p.pos], 64)
+		if err != nil {
+			p.pos = start
+			return nil, p.errorf("bad number")
+		}
+		return &exprNode{op: "num", num: v}, nil
+	case unicode.IsLetter(rune(c)):
+		start := p.pos
+		for p.pos < len(p.s) && unicode.IsLetter(rune(p.s[p.pos])) {
+			p.pos++
+		}
+		name := strings.ToLower(p.s[start:p.pos])
+		nargs := 1
+		switch {
+		case exprAggs[name] != nil, name == "abs":
+		case name == "round":
+			nargs = 2
+		default:
+			p.pos = start
+			return nil, p.errorf("unknown function %q", name)
+		}
+		if p.peek() != '(' {
+			return nil, p.errorf("missing (")
+		}
+		p.pos++
+		n := &exprNode{op: name}
+		for i := 0; i < nargs; i++ {
+			if i > 0 {
+				if p.peek() != ',' {
+					return nil, p.errorf("missing ,")
+				}
+				p.pos++
+			}
+			a, err := p.parseSum()
+			if err != nil {
+				return nil, err
+			}
+			n.args = append(n.args, a)
+		}
+		if p.peek() != ')' {
+			return nil, p.errorf("missing )")
+		}
+		p.pos++
+		if exprAggs[name] != nil && n.args[0].op != "col" {
+			return nil, p.errorf("%s needs a column, like %s([Amount])", name, name)
+		}
+		return n, nil
+	}
+	return nil, p.errorf("unexpected %q", c)
+}
@@ -0,0 +1,109 @@
+package gotable
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestFormula(t *testing.T) {
+	var tbl Table
+	tbl.Init()
+	tbl.AddColumn("Item", 10, CELLSTRING, COLJUSTIFYLEFT)
+	tbl.AddColumn("Qty", 5, CELLINT, COLJUSTIFYRIGHT)
+	tbl.AddColumn("Price", 10, CELLFLOAT, COLJUSTIFYRIGHT)
+	tbl.AddColumn("Amount", 10, CELLFLOAT, COLJUSTIFYRIGHT)
+	tbl.AddColumn("Share", 10, CELLFLOAT, COLJUSTIFYRIGHT)
+	tbl.AddColumn("Note", 10, CELLSTRING, COLJUSTIFYLEFT)
+
+	var data = []struct {
+		item  string
+		qty   int64
+		price float64
+	}{
+		{"apple", 4, 1.5}, {"pear", 2, 2.0}, {"plum", 6, 3.0},
+	}
+	for i := 0; i < len(data); i++ {
+		tbl.AddRow()
+		tbl.Puts(-1, 0, data[i].item)
+		tbl.Puti(-1, 1, data[i].qty)
+		tbl.Putf(-1, 2, data[i].price)
+	}
+	tbl.AddRow() // no price: its computed cells are null
+	tbl.Puts(-1, 0, "fig")
+	tbl.Puti(-1, 1, 3)
+
+	if err := tbl.SetColExpr(3, "[Qty] * [Price]"); err != nil {
+		t.Errorf("formula_test: SetColExpr: %s\n", err.Error())
+	}
+	if err := tbl.SetColExpr(4, "Round([Amount] / Sum([Amount]) * 100, 1)"); err != nil {
+		t.Errorf("formula_test: SetColExpr: %s\n", err.Error())
+	}
+	tbl.SetColFormula(5, func(t *Table, row int) Cell {
+		var c Cell
+		if t.Get(row, 1).Ival > 3 {
+			c.Type = CELLSTRING
+			c.Sval = "bulk"
+		}
+		return c
+	})
+	tbl.Recompute()
+
+	expect := []float64{6, 4, 18}
+	share := []float64{21.4, 14.3, 64.3}
+	for i := 0; i < len(expect); i++ {
+		if c := tbl.Get(i, 3); c.Type != CELLFLOAT || math.Abs(c.Fval-expect[i]) > 0.000001 {
+			t.Errorf("formula_test: row %d Amount: expected %f, found %#v\n", i, expect[i], c)
+		}
+		if c := tbl.Get(i, 4); c.Type != CELLFLOAT || math.Abs(c.Fval-share[i]) > 0.000001 {
+			t.Errorf("formula_test: row %d Share: expected %f, found %#v\n", i, share[i], c)
+		}
+	}
+	if !tbl.IsNull(3, 3) {
+		t.Errorf("formula_test: expected null Amount for a row without a price, found %#v\n", tbl.Get(3, 3))
+	}
+	if tbl.Get(0, 5).Sval != "bulk" || tbl.Get(1, 5).Type != 0 {
+		t.Errorf("formula_test: callback column was not computed\n")
+	}
+
+	// values follow changes to the cells they are computed from, and the
+	// columns they refer to can move
+	tbl.Putf(1, 2, 4.0)
+	tbl.MoveColumn(2, 0)
+	s, _ := tbl.SprintTable()
+	if !strings.Contains(s, "8.00") {
+		t.Errorf("formula_test: changed Price was not used when printing:\n%s\n", s)
+	}
+	if c := tbl.Get(1, 3); c.Fval != 8 {
+		t.Errorf("formula_test: after MoveColumn expected Amount 8, found %#v\n", c)
+	}
+
+	// an integer column rounds its result
+	tbl.InsertColumn(6, "Half", 5, CELLINT, COLJUSTIFYRIGHT)
+	tbl.SetColExpr(6, "-[Qty] / 4")
+	tbl.Recompute()
+	if c := tbl.Get(2, 6); c.Type != CELLINT || c.Ival != -2 {
+		t.Errorf("formula_test: expected -2, found %#v\n", c)
+	}
+	if c := tbl.Get(3, 6); c.Type != CELLINT || c.Ival != -1 {
+		t.Errorf("formula_test: expected -1, found %#v\n", c)
+	}
+
+	var bad = []string{
+		"[Qty] *",
+		"[Qty",
+		"Foo([Qty])",
+		"Sum(2)",
+		"([Qty] + 1",
+		"[Nothing] + 1",
+		"[Qty] 2",
+	}
+	for i := 0; i < len(bad); i++ {
+		if err := tbl.SetColExpr(6, bad[i]); err == nil {
+			t.Errorf("formula_test: expected an error for %q\n", bad[i])
+		}
+	}
+	if err := tbl.SetColExpr(1, "1"); err == nil {
+		t.Errorf("formula_test: expected an error for a string column\n")
+	}
+}
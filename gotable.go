@@ -2,6 +2,7 @@ package gotable
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"sort"
@@ -28,11 +29,16 @@ const (
 	CELLSTRING   = 3
 	CELLDATE     = 4
 	CELLDATETIME = 5
+	CELLNULL     = 6 // a cell that explicitly has no value, see PutNull
+	CELLDECIMAL  = 7 // an exact fixed-point number, see Decimal
 
-	TABLEOUTTEXT = 1
-	TABLEOUTHTML = 2
-	TABLEOUTPDF  = 3
-	TABLEOUTCSV  = 4
+	TABLEOUTTEXT     = 1
+	TABLEOUTHTML     = 2
+	TABLEOUTPDF      = 3
+	TABLEOUTCSV      = 4
+	TABLEOUTJSON     = 5
+	TABLEOUTMARKDOWN = 6
+	TABLEOUTXLSX     = 7
 
 	CSSFONTSIZE = 14
 	NEWLINE     = "\n"
@@ -45,6 +51,7 @@ type Cell struct {
 	Fval float64   // float value
 	Sval string    // string value
 	Dval time.Time // datetime value
+	Mval Decimal   // decimal value
 }
 
 // ColumnDef defines a Table column -- a column title, justification, and formatting
@@ -58,12 +65,21 @@ type ColumnDef struct {
 	Hdr       []string // multiple lines of column headers as needed -- based on width and Title
 	Fdecimals int      // the number of decimal digits for floating point numbers. The default is 2
 	HTMLWidth int
+	Formula   FormulaFunc    // if set, the cells of this column are computed. See SetColFormula
+	NullText  string         // printed for null cells in this column. See SetColNullText
+	Currency  string         // currency symbol printed before CELLDECIMAL values. See SetColDecimal
+	Rounding  int            // rounding mode for CELLDECIMAL values, e.g. ROUNDHALFEVEN
+	NumFmt    *NumberFormat  // separators and negative style of numbers. See SetColNumberFormat
+	DateFmt   string         // format of dates in this column, "" for the table's format. See SetColDateFormat
+	Location  *time.Location // time zone datetime values in this column are shown in. See SetColDateFormat
 }
 
 // Colset defines a set of Cells
 type Colset struct {
 	Col    []Cell // 1 row's worth of Cells, contains len(Col) number of Cells
 	Height int    // height of row
+	id     int    // identity of the row, it does not change when the row moves
+	total  bool   // a total row added by GroupBy, left alone by formulas
 }
 
 // Rowset defines a set of rows to be operated on at a later time.
@@ -83,6 +99,7 @@ type Table struct {
 	maxHdrRows      int                                // maximum number of header rows across all ColDefs
 	DateFmt         string                             // format for printing dates
 	DateTimeFmt     string                             // format for datetime values
+	Location        *time.Location                     // time zone datetime values are shown in, nil shows them as stored
 	LineAfter       []int                              // array of row numbers that have a horizontal line after they are printed
 	LineBefore      []int                              // array of row numbers that have a horizontal line before they are printed
 	RS              []Rowset                           // a list of rowsets
@@ -90,6 +107,15 @@ type Table struct {
 	htmlTemplate    string                             // path of custom html template path
 	htmlTemplateCSS string                             // path of custom css for html template
 	fontUnit        string                             // font units in html, e.g, px/ch
+	lastRowID       int                                // the most recently assigned row identity
+	aggCache        map[string]Cell                    // column aggregates used by computed columns during Recompute
+	nullText        string                             // printed for null cells in columns that have no NullText
+	csvNullText     string                             // written for null cells in csv output
+	pdfRenderer     PDFRenderer                        // pdf backend, nil for the package default
+	textOptions     *TextOptions                       // pagination of text output
+	textBorder      *TextBorder                        // border style of text output
+	textANSI        bool                               // format rule styles as ANSI escape sequences in text output
+	formatRules     []FormatRule                       // conditional formatting, see AddFormatRule
 	// errorList       []string                           // stores the list of error in string format
 }
 
@@ -233,6 +259,19 @@ func (t *Table) AddLineBefore(row int) {
 	sort.Ints(t.LineBefore)
 }
 
+// hasLineAfter reports whether a line is printed after row
+func (t *Table) hasLineAfter(row int) bool {
+	j := sort.SearchInts(t.LineAfter, row)
+	return j < len(t.LineAfter) && t.LineAfter[j] == row
+}
+
+// hasLineBefore reports whether a line is printed before row. If the previous
+// row already has a line after it, the line is not printed twice.
+func (t *Table) hasLineBefore(row int) bool {
+	j := sort.SearchInts(t.LineBefore, row)
+	return j < len(t.LineBefore) && t.LineBefore[j] == row && !t.hasLineAfter(row-1)
+}
+
 // CreateRowset creates a new rowset. You can add row indeces to it.  You can process the rows at those indeces later.
 // The return value is the Rowset identifier; rsid.  Use it to refer to this rowset.
 func (t *Table) CreateRowset() int {
@@ -266,6 +305,8 @@ func (t *Table) SumRowset(rsid, col int) Cell {
 		case CELLFLOAT:
 			c.Type = CELLFLOAT
 			c.Fval += t.Row[row].Col[col].Fval
+		case CELLDECIMAL:
+			c = addDecimal(c, t.Row[row].Col[col].Mval)
 		}
 	}
 	return c
@@ -281,7 +322,7 @@ func (t *Table) AdjustFormatString(cd *ColumnDef) {
 	switch cd.CellType {
 	case CELLINT:
 		cd.Pfmt = fmt.Sprintf("%%%s%dd", lft, cd.Width)
-	case CELLFLOAT:
+	case CELLFLOAT, CELLDECIMAL:
 		cd.Pfmt = fmt.Sprintf("%%%d.%ds", cd.Width, cd.Width)
 	case CELLSTRING:
 		cd.Pfmt = fmt.Sprintf("%%%s%d.%ds", lft, cd.Width, cd.Width)
@@ -406,6 +447,43 @@ func (t *Table) Type(row, col int) int {
 	return t.Row[row].Col[col].Type
 }
 
+// cellString returns the value of cell c as a string, formatted the same way
+// as in the text output but without any padding
+func (t *Table) cellString(c *Cell) string {
+	switch c.Type {
+	case CELLINT:
+		return strconv.FormatInt(c.Ival, 10)
+	case CELLFLOAT:
+		return defaultNumberFormat.formatFloat(c.Fval, 2)
+	case CELLDECIMAL:
+		return c.Mval.format("", ",", ".")
+	case CELLSTRING:
+		return c.Sval
+	case CELLDATE:
+		return c.Dval.Format(t.DateFmt)
+	case CELLDATETIME:
+		return c.Dval.Format(t.DateTimeFmt)
+	}
+	return ""
+}
+
+// cellText returns the text shown for cell c of column col, without padding
+func (t *Table) cellText(col int, c *Cell) string {
+	switch c.Type {
+	case CELLINT:
+		return t.intString(col, c.Ival)
+	case CELLFLOAT:
+		return t.floatString(col, c.Fval)
+	case CELLDECIMAL:
+		return t.decimalString(col, c)
+	case CELLDATE, CELLDATETIME:
+		return t.dateString(col, c)
+	case CELLNULL:
+		return t.colNullText(col)
+	}
+	return t.cellString(c)
+}
+
 // Puti updates the Cell at row,col with the int64 value v
 // and sets its type to CELLINT. If row or col is out of
 // bounds the return value is false. Otherwise, the return
@@ -510,6 +588,7 @@ func (t *Table) createColSet(c *Colset) {
 		c.Col = append(c.Col, cell)
 	}
 	c.Height = 1
+	c.id = t.newRowID()
 }
 
 // Sum computes the sum of the rows at the specified column index. It returns a Cell
@@ -534,6 +613,8 @@ func (t *Table) SumRows(col, from, to int) Cell {
 		case CELLFLOAT:
 			c.Type = CELLFLOAT
 			c.Fval += t.Row[i].Col[col].Fval
+		case CELLDECIMAL:
+			c = addDecimal(c, t.Row[i].Col[col].Mval)
 		}
 	}
 	return c
@@ -549,29 +630,6 @@ func (t *Table) InsertSumRow(row, from, to int, cols []int) {
 	}
 }
 
-// Sort sorts rows (from,to) by column col ascending
-func (t *Table) Sort(from, to, col int) {
-	// fmt.Printf("Table.Sort:  from = %d, to = %d, col = %d,  len(t.Row) = %d\n", from, to, col, len(t.Row))
-	var swap bool
-	for i := from; i < to; i++ {
-		for j := i + 1; j <= to; j++ {
-			switch t.Row[i].Col[col].Type {
-			case CELLINT:
-				swap = t.Row[i].Col[col].Ival > t.Row[j].Col[col].Ival
-			case CELLFLOAT:
-				swap = t.Row[i].Col[col].Fval > t.Row[j].Col[col].Fval
-			case CELLSTRING:
-				swap = strings.ToLower(t.Row[i].Col[col].Sval) > strings.ToLower(t.Row[j].Col[col].Sval)
-			case CELLDATE, CELLDATETIME:
-				swap = t.Row[i].Col[col].Dval.After(t.Row[j].Col[col].Dval)
-			}
-			if swap {
-				t.Row[i], t.Row[j] = t.Row[j], t.Row[i]
-			}
-		}
-	}
-}
-
 // InsertSumRowsetCols sums the values for the specified rowset and appends it at the specified row
 // rsid = the RowSet on which to perform the sum
 // row  = a row will be inserted at this index, and the totals will be added to this row
@@ -592,7 +650,9 @@ func (t *Table) AddRow() {
 	t.Row = append(t.Row, c)
 }
 
-// InsertRow adds a new Row at the specified index.
+// InsertRow adds a new Row at the specified index. Line markers, rowsets and
+// cell CSS stay with the rows they were attached to. The new row is added to
+// every rowset.
 func (t *Table) InsertRow(row int) {
 	if row >= len(t.Row) || row < 0 {
 		t.AddRow()
@@ -600,60 +660,197 @@ func (t *Table) InsertRow(row int) {
 	}
 	var c Colset
 	t.createColSet(&c)
-	t.Row = append(t.Row[:row+1], t.Row[row:]...)
-	t.Row[row] = c
+	rows := make([]Colset, 0, len(t.Row)+1)
+	rows = append(rows, t.Row[:row]...)
+	rows = append(rows, c)
+	rows = append(rows, t.Row[row:]...)
+	t.setRows(rows)
+
+	// add in the new row...
+	for i := 0; i < len(t.RS); i++ {
+		t.RS[i].R = append(t.RS[i].R, row)
+	}
+}
 
-	// Adjust LineAfter
-	for i := 0; i < len(t.LineAfter); i++ {
-		if t.LineAfter[i] >= row {
-			t.LineAfter[i]++
+// DeleteRow removes the table row at the specified index. All rowsets and line markers are adjusted,
+// and any of them that referred to the deleted row are removed along with its cell CSS.
+func (t *Table) DeleteRow(row int) {
+	if row < 0 || row >= len(t.Row) {
+		return
+	}
+	id := t.rowID(row)
+	rows := make([]Colset, 0, len(t.Row)-1)
+	rows = append(rows, t.Row[:row]...)
+	rows = append(rows, t.Row[row+1:]...)
+	t.setRows(rows)
+
+	// Clean up the CSS of the deleted row
+	prefix := `row:` + strconv.Itoa(id) + `-`
+	for k := range t.CSS {
+		if strings.HasPrefix(k, prefix) {
+			delete(t.CSS, k)
 		}
 	}
-	// Adjust RowSets
-	for i := 0; i < len(t.RS); i++ {
-		// adjust the existing rows...
-		for j := 0; j < len(t.RS[i].R); j++ {
-			if t.RS[i].R[j] >= row {
-				t.RS[i].R[j]++
+}
+
+// MoveRow moves the row at index from so that it ends up at index to. Line markers,
+// rowsets and cell CSS move with it.
+func (t *Table) MoveRow(from, to int) {
+	if from < 0 || from >= len(t.Row) || to < 0 || to >= len(t.Row) || from == to {
+		return
+	}
+	c := t.Row[from]
+	rows := make([]Colset, 0, len(t.Row))
+	rows = append(rows, t.Row[:from]...)
+	rows = append(rows, t.Row[from+1:]...)
+	rows = append(rows[:to], append([]Colset{c}, rows[to:]...)...)
+	t.setRows(rows)
+}
+
+// RowID returns the identity of the row at index row. The identity does not change
+// when rows are sorted, inserted, deleted or moved. It returns 0 if row is out of bounds.
+func (t *Table) RowID(row int) int {
+	if row < 0 || row >= len(t.Row) {
+		return 0
+	}
+	return t.rowID(row)
+}
+
+// FindRow returns the current index of the row with identity id, or -1 if
+// there is no such row.
+func (t *Table) FindRow(id int) int {
+	for i := 0; i < len(t.Row); i++ {
+		if t.Row[i].id == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// newRowID returns an identity that is not used by any row in the table
+func (t *Table) newRowID() int {
+	t.lastRowID++
+	return t.lastRowID
+}
+
+// rowID returns the identity of the row at index row. Rows that were not created
+// through the Table methods get their identity the first time it is needed.
+func (t *Table) rowID(row int) int {
+	if t.Row[row].id == 0 {
+		for i := 0; i < len(t.Row); i++ {
+			if t.Row[i].id > t.lastRowID {
+				t.lastRowID = t.Row[i].id
 			}
 		}
-		// add in the new row...
-		t.RS[i].R = append(t.RS[i].R, row)
+		t.Row[row].id = t.newRowID()
 	}
+	return t.Row[row].id
 }
 
-// DeleteRow removes the table row at the specified index. All rowsets and LineAfter sets are adjusted.
-// Cleanup on LineAfter and RowSets does not work if row == 0. I was just too lazy at the time to add this
-// code because I know how/where delete will be used and it will not affect row 0.
-func (t *Table) DeleteRow(row int) {
-	t.Row = t.Row[:row+copy(t.Row[row:], t.Row[row+1:])] // this removes t.Row[row]
-	// Clean up LineAfter
-	for i := 0; i < len(t.LineAfter); i++ {
-		if t.LineAfter[i] > row {
-			t.LineAfter[i]--
+// rowMarks holds the line markers and rowsets of a table by row identity rather
+// than row index. Markers beyond the last row are kept as negative offsets from
+// the end of the table so that they still apply to rows appended later.
+type rowMarks struct {
+	lineAfter, lineBefore []int
+	rs                    [][]int
+}
+
+// markToID converts a row index used in a line marker or rowset to a row identity
+func (t *Table) markToID(row int) int {
+	if row >= len(t.Row) {
+		return -(row - len(t.Row) + 1)
+	}
+	return t.rowID(row)
+}
+
+// markToIndex converts the result of markToID back to a row index. It returns
+// false if the row no longer exists.
+func (t *Table) markToIndex(id int, idx map[int]int) (int, bool) {
+	if id < 0 {
+		return len(t.Row) - id - 1, true
+	}
+	row, ok := idx[id]
+	return row, ok
+}
+
+// saveRowMarks records the line markers and rowsets by row identity
+func (t *Table) saveRowMarks() rowMarks {
+	var m rowMarks
+	conv := func(a []int) []int {
+		var r []int
+		for i := 0; i < len(a); i++ {
+			if a[i] >= 0 {
+				r = append(r, t.markToID(a[i]))
+			}
 		}
+		return r
 	}
-	// Clean up RowSets
+	m.lineAfter = conv(t.LineAfter)
+	m.lineBefore = conv(t.LineBefore)
 	for i := 0; i < len(t.RS); i++ {
-		l := len(t.RS[i].R) // length of jth rowset
-		// First remove the row from the rowset
-		for j := 0; j < l; j++ {
-			if t.RS[i].R[j] == row {
-				t.RS[i].R = append(t.RS[i].R[:j], t.RS[i].R[j+1:]...) // this append statement removes element j from the slice
-				break
+		m.rs = append(m.rs, conv(t.RS[i].R))
+	}
+	return m
+}
+
+// restoreRowMarks sets the line markers and rowsets from m using the current row
+// indeces of the rows. Markers for rows that no longer exist are dropped.
+func (t *Table) restoreRowMarks(m rowMarks) {
+	idx := make(map[int]int, len(t.Row))
+	for i := 0; i < len(t.Row); i++ {
+		idx[t.rowID(i)] = i
+	}
+	conv := func(a []int) []int {
+		r := []int{}
+		for i := 0; i < len(a); i++ {
+			if row, ok := t.markToIndex(a[i], idx); ok {
+				r = append(r, row)
 			}
 		}
-		// Now adjust the row numbers of the remaining rows
-		l = len(t.RS[i].R)
-		for j := 0; j < l; j++ {
-			if t.RS[i].R[j] >= row {
-				t.RS[i].R[j]--
+		return r
+	}
+	if t.LineAfter != nil {
+		t.LineAfter = conv(m.lineAfter)
+		sort.Ints(t.LineAfter)
+	}
+	if t.LineBefore != nil {
+		t.LineBefore = conv(m.lineBefore)
+		sort.Ints(t.LineBefore)
+	}
+	for i := 0; i < len(t.RS); i++ {
+		t.RS[i].R = conv(m.rs[i])
+	}
+}
+
+// setRows replaces the rows of the table with rows, which must hold the
+// same rows in a different order, possibly with some added or removed. The
+// line markers and rowsets follow the rows they refer to. Cell CSS is keyed
+// by row identity, so it needs no adjustment.
+func (t *Table) setRows(rows []Colset) {
+	m := t.saveRowMarks()
+	t.Row = rows
+	t.restoreRowMarks(m)
+}
+
+// setColumnWidth sets the width of column col. If width is 0 or less, the
+// column is made just wide enough for its title and its values.
+func (t *Table) setColumnWidth(col, width int) {
+	cd := &t.ColDefs[col]
+	if width <= 0 {
+		width = displayWidth(cd.ColTitle)
+		for r := 0; r < len(t.Row); r++ {
+			s := t.cellText(col, &t.Row[r].Col[col])
+			if displayWidth(s) > width {
+				width = displayWidth(s)
 			}
 		}
 	}
+	cd.Width = width
+	t.AdjustColumnHeader(cd)
+	t.AdjustFormatString(cd)
 }
 
-// TightenColumns goes through all values in STRING columns and determines the maximum length in characters (max).
+// TightenColumns goes through all values in STRING columns and determines the maximum width in terminal columns (max).
 // If this length is less than the column width the column width is reduced to max.  This is
 // mostly useful for text formatting.
 func (t *Table) TightenColumns() {
@@ -663,14 +860,14 @@ func (t *Table) TightenColumns() {
 		}
 		max := 0
 		for j := 0; j < len(t.ColDefs[i].Hdr); j++ { // first, find the max len of the col hdrs
-			l := len(t.ColDefs[i].Hdr[j])
+			l := displayWidth(t.ColDefs[i].Hdr[j])
 			if max < l {
 				max = l
 			}
 		}
 		for j := 0; j < len(t.Row); j++ { // continue by find the max width of cell values in this col
 			if t.Row[j].Col[i].Type == CELLSTRING {
-				l := len(t.Row[j].Col[i].Sval)
+				l := displayWidth(t.Row[j].Col[i].Sval)
 				if max < l {
 					max = l
 				}
@@ -764,7 +961,8 @@ func (t *Table) SprintTable() (string, error) {
 
 // FprintTable renders the entire table for io.Writer object for text output
 func (t *Table) FprintTable(w io.Writer) error {
-	var tout TableExportType = &TextTable{Table: t, TextColSpace: 2}
+	t.Recompute()
+	var tout TableExportType = &TextTable{Table: t, TextColSpace: 2, Options: t.textOptions, Border: t.textBorder, Plain: !t.textANSI}
 	return tout.writeTableOutput(w)
 }
 
@@ -775,20 +973,62 @@ func (t *Table) TextprintTable(w io.Writer) error {
 
 // CSVprintTable renders the entire table for csv output
 func (t *Table) CSVprintTable(w io.Writer) error {
+	t.Recompute()
 	var tout = &CSVTable{Table: t}
 	return tout.writeTableOutput(w)
 }
 
 // HTMLprintTable renders the entire table for html output
 func (t *Table) HTMLprintTable(w io.Writer) error {
+	t.Recompute()
 	var tout TableExportType = &HTMLTable{Table: t}
 	return tout.writeTableOutput(w)
 }
 
+// MarkdownprintTable renders the entire table for markdown output, as a GitHub
+// flavored markdown table
+func (t *Table) MarkdownprintTable(w io.Writer) error {
+	t.Recompute()
+	var tout TableExportType = &MarkdownTable{Table: t}
+	return tout.writeTableOutput(w)
+}
+
+// JSONprintTable renders the entire table for json output. The output can be
+// read back with ReadJSON. It holds the cells, the column definitions, the
+// rowsets, lines and css. Computed columns are written as values. The csv null
+// text, the text output options, the format rules and the formulas are not
+// written.
+func (t *Table) JSONprintTable(w io.Writer) error {
+	t.Recompute()
+	var tout = &JSONTable{Table: t}
+	return tout.writeTableOutput(w)
+}
+
+// XLSXprintTable renders the entire table as an xlsx workbook with a single
+// sheet. Numbers and dates are written as numeric cells.
+func (t *Table) XLSXprintTable(w io.Writer) error {
+	t.Recompute()
+	var tout = &XLSXTable{Table: t}
+	return tout.writeTableOutput(w)
+}
+
 // PDFprintTable renders the entire table for pdf output
 func (t *Table) PDFprintTable(w io.Writer, pdfProps []*PDFProperty) error {
+	return t.PDFprintTableContext(context.Background(), w, pdfProps)
+}
+
+// PDFprintTableContext is like PDFprintTable. The rendering is stopped when
+// ctx is done, for example after a timeout.
+func (t *Table) PDFprintTableContext(ctx context.Context, w io.Writer, pdfProps []*PDFProperty) error {
+	return t.PDFprintTableOptions(ctx, w, &PDFOptions{Extra: pdfProps})
+}
+
+// PDFprintTableOptions renders the entire table for pdf output using the page
+// settings in opts. The rendering is stopped when ctx is done.
+func (t *Table) PDFprintTableOptions(ctx context.Context, w io.Writer, opts *PDFOptions) error {
+	t.Recompute()
 	var tout = &PDFTable{Table: t}
-	return tout.writeTableOutput(w, pdfProps)
+	return tout.writeTableOutput(ctx, w, opts)
 }
 
 // ==========================
@@ -810,9 +1050,10 @@ func (cp CSSProperty) String() string {
 	return `"` + cp.Name + `:` + cp.Value + `;"`
 }
 
-// getCSSMapKeyForCell format and returns key for cell for css properties usage
+// getCSSMapKeyForCell format and returns key for cell for css properties usage.
+// The key uses the row identity so that the css stays with the row when it moves.
 func (t *Table) getCSSMapKeyForCell(rowIndex, colIndex int) string {
-	return `row:` + strconv.Itoa(rowIndex) + `-col:` + strconv.Itoa(colIndex)
+	return `row:` + strconv.Itoa(t.rowID(rowIndex)) + `-col:` + strconv.Itoa(colIndex)
 }
 
 // getCSSMapKeyForHeaderCell format and returns key for eader cell for css properties usage
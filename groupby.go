@@ -0,0 +1,168 @@
+package gotable
+
+import (
+	"fmt"
+)
+
+// GroupByOptions controls the total rows that GroupBy adds to a table
+type GroupByOptions struct {
+	SuppressRepeats bool   // blank out key values that are the same as in the previous data row
+	NoGrandTotal    bool   // do not add a grand total row at the end of the table
+	SubtotalFmt     string // format of the subtotal label, %s is the group's key value. Default: "%s Total"
+	GrandTotalLabel string // label of the grand total row. Default: "Grand Total"
+}
+
+// groupTotal records where a total row was placed and what its label is
+type groupTotal struct {
+	row      int    // index of the total row in the grouped table
+	labelCol int    // column that holds the label, -1 if there is no room for it
+	label    string // the label
+}
+
+// GroupBy sorts all the rows of the table by the key columns keyCols and then
+// adds a subtotal row after each group, at every nesting level, and a grand total
+// at the end. aggs maps column indeces to the aggregate used for that column in
+// the total rows. Total rows are computed from the data rows only, they have a
+// line before them, and the grand total also has a line after it. Rowsets keep
+// their data rows; total rows are not added to them. Computed columns are
+// recomputed before the totals are made. Formulas are not applied to the total
+// rows afterwards, and aggregates in expressions such as Sum([Amount]) leave
+// them out. If opts is nil the default options are used.
+func (t *Table) GroupBy(keyCols []int, aggs map[int]AggFunc, opts *GroupByOptions) error {
+	if len(keyCols) == 0 {
+		return fmt.Errorf("GroupBy: no key columns")
+	}
+	for i := 0; i < len(keyCols); i++ {
+		if err := t.HasValidColumn(keyCols[i]); err != nil {
+			return err
+		}
+	}
+	for col := range aggs {
+		if err := t.HasValidColumn(col); err != nil {
+			return err
+		}
+	}
+	if opts == nil {
+		opts = &GroupByOptions{}
+	}
+	t.Recompute()
+	subtotalFmt := opts.SubtotalFmt
+	if subtotalFmt == "" {
+		subtotalFmt = "%s Total"
+	}
+	grandTotalLabel := opts.GrandTotalLabel
+	if grandTotalLabel == "" {
+		grandTotalLabel = "Grand Total"
+	}
+	if len(t.Row) == 0 {
+		return nil
+	}
+
+	var keys []SortKey
+	for i := 0; i < len(keyCols); i++ {
+		keys = append(keys, SortKey{Col: keyCols[i]})
+	}
+	t.SortBy(0, len(t.Row)-1, keys...)
+
+	var rows []Colset
+	var totals []groupTotal
+	isTotal := map[int]bool{}
+
+	// addTotal appends a total row computed over data with the key columns
+	// before level copied from data[0]
+	addTotal := func(data []Colset, level int, label string) {
+		var c Colset
+		t.createColSet(&c)
+		c.total = true
+		for l := 0; l < level; l++ {
+			c.Col[keyCols[l]] = data[0].Col[keyCols[l]]
+		}
+		for col, f := range aggs {
+			var cells []Cell
+			for i := 0; i < len(data); i++ {
+				cells = append(cells, data[i].Col[col])
+			}
+			c.Col[col] = f(cells)
+		}
+		labelCol := -1
+		if level < len(keyCols) && t.ColDefs[keyCols[level]].CellType == CELLSTRING {
+			labelCol = keyCols[level]
+		} else {
+			for i := 0; i < len(t.ColDefs); i++ {
+				if _, ok := aggs[i]; !ok && t.ColDefs[i].CellType == CELLSTRING && c.Col[i].Type == 0 {
+					labelCol = i
+					break
+				}
+			}
+		}
+		totals = append(totals, groupTotal{row: len(rows), labelCol: labelCol, label: label})
+		isTotal[len(rows)] = true
+		rows = append(rows, c)
+	}
+
+	// group appends the rows in data, which all have the same keys before level,
+	// with a subtotal after each group of rows that share the key at level.
+	// Keys are compared the way SortBy compares them, so empty and null keys
+	// form one group.
+	var group func(data []Colset, level int)
+	group = func(data []Colset, level int) {
+		if level == len(keyCols) {
+			rows = append(rows, data...)
+			return
+		}
+		col := keyCols[level]
+		for i := 0; i < len(data); {
+			j := i + 1
+			for j < len(data) && keys[level].compare(&data[i].Col[col], &data[j].Col[col]) == 0 {
+				j++
+			}
+			group(data[i:j], level+1)
+			addTotal(data[i:j], level, fmt.Sprintf(subtotalFmt, t.cellText(col, &data[i].Col[col])))
+			i = j
+		}
+	}
+
+	data := make([]Colset, len(t.Row))
+	copy(data, t.Row)
+	group(data, 0)
+	if !opts.NoGrandTotal {
+		addTotal(data, 0, grandTotalLabel)
+	}
+
+	if opts.SuppressRepeats {
+		// data holds the original key values, in the same order as the data
+		// rows appear in rows
+		k := 0
+		for i := 0; i < len(rows); i++ {
+			if isTotal[i] {
+				continue
+			}
+			if k > 0 {
+				for l := 0; l < len(keyCols); l++ {
+					col := keyCols[l]
+					if keys[l].compare(&data[k-1].Col[col], &data[k].Col[col]) != 0 {
+						break
+					}
+					// give the row its own cells before changing them
+					if l == 0 {
+						rows[i].Col = append([]Cell(nil), rows[i].Col...)
+					}
+					rows[i].Col[col] = Cell{}
+				}
+			}
+			k++
+		}
+	}
+
+	t.setRows(rows)
+	for i := 0; i < len(totals); i++ {
+		if totals[i].labelCol >= 0 {
+			t.Puts(totals[i].row, totals[i].labelCol, totals[i].label)
+		}
+		t.AddLineBefore(totals[i].row)
+	}
+	if !opts.NoGrandTotal {
+		t.AddLineAfter(len(t.Row) - 1)
+	}
+	return nil
+}
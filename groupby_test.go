@@ -0,0 +1,130 @@
+package gotable
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGroupBy(t *testing.T) {
+	var tbl Table
+	tbl.Init()
+	tbl.AddColumn("Property", 10, CELLSTRING, COLJUSTIFYLEFT)
+	tbl.AddColumn("Unit", 10, CELLSTRING, COLJUSTIFYLEFT)
+	tbl.AddColumn("Rent", 10, CELLFLOAT, COLJUSTIFYRIGHT)
+	tbl.AddColumn("Tenants", 8, CELLINT, COLJUSTIFYRIGHT)
+
+	var data = []struct {
+		prop, unit string
+		rent       float64
+		n          int64
+	}{
+		{"Oak", "B", 200, 1}, {"Elm", "A", 100, 2}, {"Oak", "A", 300, 3}, {"Elm", "A", 50, 1}, {"Oak", "B", 25, 2},
+	}
+	for i := 0; i < len(data); i++ {
+		tbl.AddRow()
+		tbl.Puts(-1, 0, data[i].prop)
+		tbl.Puts(-1, 1, data[i].unit)
+		tbl.Putf(-1, 2, data[i].rent)
+		tbl.Puti(-1, 3, data[i].n)
+	}
+	rsid := tbl.CreateRowset()
+	tbl.AppendToRowset(rsid, 0) // Oak B 200
+
+	if err := tbl.GroupBy([]int{99}, nil, nil); err == nil {
+		t.Errorf("groupby_test: expected an error for a bad key column\n")
+	}
+	err := tbl.GroupBy([]int{0, 1}, map[int]AggFunc{2: AggSum, 3: AggCount}, &GroupByOptions{SuppressRepeats: true})
+	if err != nil {
+		t.Fatalf("groupby_test: GroupBy returned error: %s\n", err.Error())
+	}
+
+	// Elm A 100, Elm A 50, A total, Elm total, Oak A 300, A total, Oak B 200, Oak B 25, B total, Oak total, Grand total
+	var expect = []struct {
+		prop, unit string
+		rent       float64
+		n          int64
+	}{
+		{"Elm", "A", 100, 2},
+		{"", "", 50, 1},
+		{"Elm", "A Total", 150, 2},
+		{"Elm Total", "", 150, 2},
+		{"Oak", "A", 300, 3},
+		{"Oak", "A Total", 300, 1},
+		{"", "B", 200, 1},
+		{"", "", 25, 2},
+		{"Oak", "B Total", 225, 2},
+		{"Oak Total", "", 525, 3},
+		{"Grand Total", "", 675, 5},
+	}
+	if tbl.RowCount() != len(expect) {
+		t.Fatalf("groupby_test: expected %d rows, found %d\n%s", len(expect), tbl.RowCount(), tbl.String())
+	}
+	for i := 0; i < len(expect); i++ {
+		if tbl.Gets(i, 0) != expect[i].prop || tbl.Gets(i, 1) != expect[i].unit || tbl.Getf(i, 2) != expect[i].rent || tbl.Geti(i, 3) != expect[i].n {
+			t.Errorf("groupby_test: row %d: expected %v, found %q %q %f %d\n", i, expect[i], tbl.Gets(i, 0), tbl.Gets(i, 1), tbl.Getf(i, 2), tbl.Geti(i, 3))
+		}
+	}
+	if !compareIntSlices(tbl.LineBefore, []int{2, 3, 5, 8, 9, 10}) || !compareIntSlices(tbl.LineAfter, []int{10}) {
+		t.Errorf("groupby_test: unexpected line markers: before %#v, after %#v\n", tbl.LineBefore, tbl.LineAfter)
+	}
+	if rs := tbl.GetRowset(rsid); !compareIntSlices(rs, []int{6}) {
+		t.Errorf("groupby_test: Expected rowset %#v, found %#v\n", []int{6}, rs)
+	}
+
+	// the lines before the totals show up in the text output
+	if n := strings.Count(tbl.String(), "----------  ----------"); n != 8 {
+		t.Errorf("groupby_test: expected 8 lines in text output, found %d\n%s", n, tbl.String())
+	}
+}
+
+func TestGroupByNullKeys(t *testing.T) {
+	var tbl Table
+	tbl.Init()
+	tbl.AddColumn("Property", 10, CELLSTRING, COLJUSTIFYLEFT)
+	tbl.AddColumn("Rent", 10, CELLFLOAT, COLJUSTIFYRIGHT)
+	for i := 0; i < 4; i++ {
+		tbl.AddRow()
+		if i%2 == 0 {
+			tbl.PutNull(-1, 0)
+		}
+		tbl.Putf(-1, 1, 100)
+	}
+	if err := tbl.GroupBy([]int{0}, map[int]AggFunc{1: AggSum}, &GroupByOptions{NoGrandTotal: true}); err != nil {
+		t.Fatalf("groupby_test: GroupBy returned error: %s\n", err.Error())
+	}
+	if tbl.RowCount() != 5 {
+		t.Errorf("groupby_test: expected one group for empty and null keys, found %d rows\n", tbl.RowCount())
+	}
+	if tbl.Getf(4, 1) != 400 {
+		t.Errorf("groupby_test: expected a subtotal of 400, found %f\n", tbl.Getf(4, 1))
+	}
+}
+
+func TestGroupByFormulas(t *testing.T) {
+	var tbl Table
+	tbl.Init()
+	tbl.AddColumn("Tenant", 8, CELLSTRING, COLJUSTIFYLEFT)
+	tbl.AddColumn("Amount", 10, CELLFLOAT, COLJUSTIFYRIGHT)
+	tbl.AddColumn("Percent", 10, CELLFLOAT, COLJUSTIFYRIGHT)
+	amounts := []float64{10, 30, 60}
+	for i := 0; i < len(amounts); i++ {
+		tbl.AddRow()
+		tbl.Puts(-1, 0, []string{"A", "A", "B"}[i])
+		tbl.Putf(-1, 1, amounts[i])
+	}
+	if err := tbl.SetColExpr(2, "[Amount] / Sum([Amount]) * 100"); err != nil {
+		t.Fatalf("groupby_test: SetColExpr: %s\n", err.Error())
+	}
+	if err := tbl.GroupBy([]int{0}, map[int]AggFunc{1: AggSum, 2: AggSum}, nil); err != nil {
+		t.Fatalf("groupby_test: GroupBy returned error: %s\n", err.Error())
+	}
+
+	// the totals are not counted in Sum([Amount]) and keep their sums
+	tbl.Recompute()
+	expect := []float64{10, 30, 40, 60, 60, 100}
+	for i := 0; i < len(expect); i++ {
+		if f := tbl.Getf(i, 2); f != expect[i] {
+			t.Errorf("groupby_test: row %d: expected percent %g, found %g\n", i, expect[i], f)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package gotable
 import (
 	"bytes"
 	"fmt"
+	"html"
 	"io"
 	"io/ioutil"
 	"path"
@@ -10,7 +11,6 @@ import (
 	"strconv"
 	"text/template"
 
-	"github.com/dustin/go-humanize"
 	"github.com/kardianos/osext"
 	"github.com/yosssi/gohtml"
 )
@@ -361,14 +361,8 @@ func (ht *HTMLTable) formatRow(rowIndex int) (string, error) {
 	var tRow bytes.Buffer
 	var trClass string
 
-	if len(ht.Table.LineBefore) > 0 {
-		j := sort.SearchInts(ht.Table.LineBefore, rowIndex)
-		// line separator added in `LineAfter`??
-		// If YES, then discard it
-		sepExist := sort.SearchInts(ht.Table.LineAfter, rowIndex-1) < ht.Table.RowCount()
-		if j < len(ht.Table.LineBefore) && rowIndex == ht.Table.LineBefore[j] && !sepExist {
-			trClass += `top-line`
-		}
+	if ht.Table.hasLineBefore(rowIndex) {
+		trClass += `top-line`
 	}
 
 	// fill the content in rowTextList for the first line
@@ -378,26 +372,32 @@ func (ht *HTMLTable) formatRow(rowIndex int) (string, error) {
 		// append content in TD
 		switch ht.Table.Row[rowIndex].Col[colIndex].Type {
 		case CELLFLOAT:
-			rowCell = fmt.Sprintf(ht.Table.ColDefs[colIndex].Pfmt, humanize.FormatFloat("#,###.##", ht.Table.Row[rowIndex].Col[colIndex].Fval))
+			rowCell = fmt.Sprintf(ht.Table.ColDefs[colIndex].Pfmt, ht.Table.floatString(colIndex, ht.Table.Row[rowIndex].Col[colIndex].Fval))
 		case CELLINT:
-			rowCell = fmt.Sprintf(ht.Table.ColDefs[colIndex].Pfmt, ht.Table.Row[rowIndex].Col[colIndex].Ival)
+			rowCell = ht.Table.sprintInt(colIndex, ht.Table.Row[rowIndex].Col[colIndex].Ival)
+		case CELLDECIMAL:
+			rowCell = fmt.Sprintf(ht.Table.ColDefs[colIndex].Pfmt, ht.Table.decimalString(colIndex, &ht.Table.Row[rowIndex].Col[colIndex]))
 		case CELLSTRING:
 			// ******************************************************
 			// FOR HTML, APPEND FULL STRING, THERE ARE NO
 			// MULTILINE TEXT IN THIS
 			// ******************************************************
 			rowCell = fmt.Sprintf("%s", ht.Table.Row[rowIndex].Col[colIndex].Sval)
-		case CELLDATE:
-			rowCell = fmt.Sprintf("%*.*s", ht.Table.ColDefs[colIndex].Width, ht.Table.ColDefs[colIndex].Width, ht.Table.Row[rowIndex].Col[colIndex].Dval.Format(ht.Table.DateFmt))
-		case CELLDATETIME:
-			rowCell = fmt.Sprintf("%*.*s", ht.Table.ColDefs[colIndex].Width, ht.Table.ColDefs[colIndex].Width, ht.Table.Row[rowIndex].Col[colIndex].Dval.Format(ht.Table.DateTimeFmt))
+		case CELLDATE, CELLDATETIME:
+			rowCell = fmt.Sprintf("%*.*s", ht.Table.ColDefs[colIndex].Width, ht.Table.ColDefs[colIndex].Width, ht.Table.dateString(colIndex, &ht.Table.Row[rowIndex].Col[colIndex]))
+		case CELLNULL:
+			rowCell = html.EscapeString(ht.Table.colNullText(colIndex))
 		default:
 			rowCell = mkstr(ht.Table.ColDefs[colIndex].Width, ' ')
 		}
 
 		// format td cell with custom class if exists for it
 		g := ht.Table.getCSSMapKeyForCell(rowIndex, colIndex)
-		if cellCSSProps, ok := ht.getCSSPropertyList(g); ok {
+		cellCSSProps, ok := ht.getCSSPropertyList(g)
+		if st := ht.Table.ruleStyle(rowIndex, colIndex); st != nil {
+			cellCSSProps, ok = mergeCSSProperties(st.css(), cellCSSProps), true
+		}
+		if ok {
 
 			tdClass := `cell-row-` + strconv.Itoa(rowIndex) + `-col-` + strconv.Itoa(colIndex)
 
@@ -411,11 +411,11 @@ func (ht *HTMLTable) formatRow(rowIndex int) (string, error) {
 		}
 	}
 
-	if len(ht.Table.LineAfter) > 0 {
-		j := sort.SearchInts(ht.Table.LineAfter, rowIndex)
-		if j < len(ht.Table.LineAfter) && rowIndex == ht.Table.LineAfter[j] {
-			trClass += `bottom-line`
+	if ht.Table.hasLineAfter(rowIndex) {
+		if trClass != "" {
+			trClass += ` `
 		}
+		trClass += `bottom-line`
 	}
 
 	if trClass != "" {
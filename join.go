@@ -0,0 +1,178 @@
+package gotable
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// JOININNER et. al. are the kinds of join supported by Join
+const (
+	JOININNER = 1 // only rows whose key is found in both tables
+	JOINLEFT  = 2 // all rows of the left table, matched with right rows where possible
+	JOINFULL  = 3 // all rows of both tables, matched where possible
+)
+
+// uniqueTitle returns title, or title with a number appended if it is already in use
+func uniqueTitle(title string, used map[string]bool) string {
+	s := title
+	for i := 2; used[s]; i++ {
+		s = fmt.Sprintf("%s (%d)", title, i)
+	}
+	used[s] = true
+	return s
+}
+
+// isNumericType reports whether cells of type celltype hold a number
+func isNumericType(celltype int) bool {
+	return celltype == CELLINT || celltype == CELLFLOAT || celltype == CELLDECIMAL
+}
+
+// joinKey returns the key used by Join to match c. Numbers are equal when
+// their values are equal whatever their types, so 100, 100.0 and the decimal
+// 100.00 match. Strings are compared without regard to case, as in Sort.
+func joinKey(c *Cell) cellKey {
+	switch c.Type {
+	case CELLINT:
+		return cellKey{Type: CELLDECIMAL, Sval: strconv.FormatInt(c.Ival, 10)}
+	case CELLFLOAT:
+		if d, err := ParseDecimal(strconv.FormatFloat(c.Fval, 'f', -1, 64)); err == nil {
+			return cellKey{Type: CELLDECIMAL, Sval: d.normalized().String()}
+		}
+		return keyOf(c) // too large or not a number, it only matches the same float
+	case CELLDECIMAL:
+		return cellKey{Type: CELLDECIMAL, Sval: c.Mval.normalized().String()}
+	case CELLSTRING:
+		return cellKey{Type: CELLSTRING, Sval: strings.ToLower(c.Sval)}
+	}
+	return keyOf(c)
+}
+
+// Join combines the rows of this table, the left table, with the rows of the
+// right table that have the same value in their key columns. The new table
+// has all the columns of the left table followed by the columns of the right
+// table other than its key column. A right column whose title is already in use
+// gets a number appended to its title. joinType is one of JOININNER, JOINLEFT or
+// JOINFULL. Cells for a side that has no matching row are empty. Rows from the
+// right table that match no left row have their key placed in the left key
+// column. Empty keys never match. Numbers match by value, whether they are
+// CELLINT, CELLFLOAT or CELLDECIMAL, and strings match without regard to case,
+// as in Sort. The key columns must both be numeric or have the same type.
+// The computed columns of both tables are recomputed and copied as values; the
+// new table does not keep their formulas.
+func (t *Table) Join(right *Table, leftCol, rightCol, joinType int) (*Table, error) {
+	if err := t.HasValidColumn(leftCol); err != nil {
+		return nil, err
+	}
+	if err := right.HasValidColumn(rightCol); err != nil {
+		return nil, err
+	}
+	if joinType != JOININNER && joinType != JOINLEFT && joinType != JOINFULL {
+		return nil, fmt.Errorf("Join: unknown join type: %d", joinType)
+	}
+	lt, rt := t.ColDefs[leftCol].CellType, right.ColDefs[rightCol].CellType
+	if lt != rt && !(isNumericType(lt) && isNumericType(rt)) {
+		return nil, fmt.Errorf("Join: key columns have different types: %s and %s", cellTypeNames[lt], cellTypeNames[rt])
+	}
+
+	t.Recompute()
+	right.Recompute()
+
+	var j Table
+	j.Init()
+	j.DateFmt = t.DateFmt
+	j.DateTimeFmt = t.DateTimeFmt
+	j.Location = t.Location
+
+	// merge the column definitions. The formulas refer to the columns of
+	// their own table, so they are dropped and the computed values kept.
+	used := map[string]bool{}
+	for i := 0; i < len(t.ColDefs); i++ {
+		cd := t.ColDefs[i]
+		cd.Formula = nil
+		cd.ColTitle = uniqueTitle(cd.ColTitle, used)
+		j.ColDefs = append(j.ColDefs, cd)
+	}
+	var rcols []int // columns of the right table, in the order they appear in j
+	for i := 0; i < len(right.ColDefs); i++ {
+		if i == rightCol {
+			continue
+		}
+		cd := right.ColDefs[i]
+		cd.Formula = nil
+		if title := uniqueTitle(cd.ColTitle, used); title != cd.ColTitle {
+			cd.ColTitle = title
+			j.AdjustColumnHeader(&cd)
+			j.AdjustFormatString(&cd)
+		}
+		j.ColDefs = append(j.ColDefs, cd)
+		rcols = append(rcols, i)
+	}
+
+	// index the rows of the right table by key
+	index := map[cellKey][]int{}
+	for i := 0; i < len(right.Row); i++ {
+		c := &right.Row[i].Col[rightCol]
+		if isNullCell(c) {
+			continue
+		}
+		k := joinKey(c)
+		index[k] = append(index[k], i)
+	}
+
+	nleft := len(t.ColDefs)
+	addRow := func(l, r int) {
+		j.AddRow()
+		if l >= 0 {
+			copy(j.Row[len(j.Row)-1].Col, t.Row[l].Col)
+		}
+		if r >= 0 {
+			for i := 0; i < len(rcols); i++ {
+				j.Row[len(j.Row)-1].Col[nleft+i] = right.Row[r].Col[rcols[i]]
+			}
+			if l < 0 {
+				j.Row[len(j.Row)-1].Col[leftCol] = right.Row[r].Col[rightCol]
+			}
+		}
+	}
+
+	matched := make([]bool, len(right.Row))
+	for i := 0; i < len(t.Row); i++ {
+		c := &t.Row[i].Col[leftCol]
+		var rows []int
+		if !isNullCell(c) {
+			rows = index[joinKey(c)]
+		}
+		for k := 0; k < len(rows); k++ {
+			addRow(i, rows[k])
+			matched[rows[k]] = true
+		}
+		if len(rows) == 0 && joinType != JOININNER {
+			addRow(i, -1)
+		}
+	}
+	if joinType == JOINFULL {
+		for i := 0; i < len(right.Row); i++ {
+			if !matched[i] {
+				addRow(-1, i)
+			}
+		}
+	}
+	return &j, nil
+}
+
+// InnerJoin returns the rows of t and right that have the same key. See Join.
+func (t *Table) InnerJoin(right *Table, leftCol, rightCol int) (*Table, error) {
+	return t.Join(right, leftCol, rightCol, JOININNER)
+}
+
+// LeftJoin returns all the rows of t, with the matching rows of right. See Join.
+func (t *Table) LeftJoin(right *Table, leftCol, rightCol int) (*Table, error) {
+	return t.Join(right, leftCol, rightCol, JOINLEFT)
+}
+
+// FullOuterJoin returns all the rows of t and right, matched where the keys
+// are the same. See Join.
+func (t *Table) FullOuterJoin(right *Table, leftCol, rightCol int) (*Table, error) {
+	return t.Join(right, leftCol, rightCol, JOINFULL)
+}
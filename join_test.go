@@ -0,0 +1,135 @@
+package gotable
+
+import (
+	"testing"
+)
+
+func TestJoin(t *testing.T) {
+	var acct, bal Table
+	acct.Init()
+	acct.AddColumn("Account", 8, CELLINT, COLJUSTIFYRIGHT)
+	acct.AddColumn("Name", 12, CELLSTRING, COLJUSTIFYLEFT)
+	bal.Init()
+	bal.AddColumn("Account", 8, CELLINT, COLJUSTIFYRIGHT)
+	bal.AddColumn("Name", 12, CELLSTRING, COLJUSTIFYLEFT)
+	bal.AddColumn("Balance", 10, CELLFLOAT, COLJUSTIFYRIGHT)
+
+	for i, name := range []string{"Cash", "Rent", "Deposits"} {
+		acct.AddRow()
+		acct.Puti(-1, 0, int64(100+i))
+		acct.Puts(-1, 1, name)
+	}
+	for _, b := range []struct {
+		acct int64
+		bal  float64
+	}{{101, 10}, {103, 30}, {100, 5}, {101, 20}} {
+		bal.AddRow()
+		bal.Puti(-1, 0, b.acct)
+		bal.Puts(-1, 1, "x")
+		bal.Putf(-1, 2, b.bal)
+	}
+
+	if _, err := acct.InnerJoin(&bal, 0, 9); err == nil {
+		t.Errorf("join_test: expected an error for a bad key column\n")
+	}
+
+	j, err := acct.InnerJoin(&bal, 0, 0)
+	if err != nil {
+		t.Fatalf("join_test: InnerJoin returned error: %s\n", err.Error())
+	}
+	titles := []string{"Account", "Name", "Name (2)", "Balance"}
+	for i := 0; i < len(titles); i++ {
+		if j.ColDefs[i].ColTitle != titles[i] {
+			t.Errorf("join_test: column %d: expected title %s, found %s\n", i, titles[i], j.ColDefs[i].ColTitle)
+		}
+	}
+	// Cash 5, Rent 10, Rent 20
+	if j.RowCount() != 3 || j.Getf(0, 3) != 5 || j.Getf(1, 3) != 10 || j.Getf(2, 3) != 20 {
+		t.Errorf("join_test: unexpected inner join result:\n%s", j.String())
+	}
+
+	j, _ = acct.LeftJoin(&bal, 0, 0)
+	if j.RowCount() != 4 || j.Gets(3, 1) != "Deposits" || j.Type(3, 3) != 0 {
+		t.Errorf("join_test: unexpected left join result:\n%s", j.String())
+	}
+
+	j, _ = acct.FullOuterJoin(&bal, 0, 0)
+	if j.RowCount() != 5 || j.Geti(4, 0) != 103 || j.Type(4, 1) != 0 || j.Getf(4, 3) != 30 {
+		t.Errorf("join_test: unexpected full outer join result:\n%s", j.String())
+	}
+}
+
+func TestJoinKeyTypes(t *testing.T) {
+	var ids, amounts Table
+	ids.Init()
+	ids.AddColumn("Id", 8, CELLINT, COLJUSTIFYRIGHT)
+	ids.AddColumn("Name", 12, CELLSTRING, COLJUSTIFYLEFT)
+	amounts.Init()
+	amounts.AddColumn("Id", 8, CELLDECIMAL, COLJUSTIFYRIGHT)
+	amounts.AddColumn("Name", 12, CELLSTRING, COLJUSTIFYLEFT)
+	for i := int64(1); i <= 3; i++ {
+		ids.AddRow()
+		ids.Puti(-1, 0, i)
+		ids.Puts(-1, 1, []string{"Cash", "RENT", "Deposits"}[i-1])
+		amounts.AddRow()
+		amounts.Putm(-1, 0, NewDecimal(i*100, 2))
+		amounts.Puts(-1, 1, []string{"cash", "Rent", "Fees"}[i-1])
+	}
+
+	// an int key matches a decimal key with the same value
+	j, err := ids.InnerJoin(&amounts, 0, 0)
+	if err != nil {
+		t.Fatalf("join_test: InnerJoin returned error: %s\n", err.Error())
+	}
+	if j.RowCount() != 3 {
+		t.Errorf("join_test: expected 3 rows joined on numeric keys, found %d\n", j.RowCount())
+	}
+	var f Table
+	f.Init()
+	f.AddColumn("Id", 8, CELLFLOAT, COLJUSTIFYRIGHT)
+	f.AddRow()
+	f.Putf(-1, 0, 2)
+	if j, _ = ids.InnerJoin(&f, 0, 0); j.RowCount() != 1 || j.Gets(0, 1) != "RENT" {
+		t.Errorf("join_test: expected an int key to match a float key\n")
+	}
+
+	// strings are matched without regard to case, as in Sort
+	if j, _ = ids.InnerJoin(&amounts, 1, 1); j.RowCount() != 2 {
+		t.Errorf("join_test: expected 2 rows joined on names, found %d\n", j.RowCount())
+	}
+
+	if _, err := ids.InnerJoin(&amounts, 0, 1); err == nil {
+		t.Errorf("join_test: expected an error for key columns of different types\n")
+	}
+}
+
+func TestJoinFormulas(t *testing.T) {
+	var acct, bal Table
+	acct.Init()
+	acct.AddColumn("Account", 8, CELLINT, COLJUSTIFYRIGHT)
+	acct.AddColumn("Rate", 8, CELLFLOAT, COLJUSTIFYRIGHT)
+	bal.Init()
+	bal.AddColumn("Account", 8, CELLINT, COLJUSTIFYRIGHT)
+	bal.AddColumn("Rate", 8, CELLFLOAT, COLJUSTIFYRIGHT)
+	bal.AddColumn("Double", 8, CELLFLOAT, COLJUSTIFYRIGHT)
+	acct.AddRow()
+	acct.Puti(-1, 0, 100)
+	acct.Putf(-1, 1, 1)
+	bal.AddRow()
+	bal.Puti(-1, 0, 100)
+	bal.Putf(-1, 1, 5)
+	if err := bal.SetColExpr(2, "[Rate] * 2"); err != nil {
+		t.Fatalf("join_test: SetColExpr: %s\n", err.Error())
+	}
+
+	// in the joined table [Rate] is the left column, so the formula must not
+	// be kept
+	j, err := acct.InnerJoin(&bal, 0, 0)
+	if err != nil {
+		t.Fatalf("join_test: InnerJoin returned error: %s\n", err.Error())
+	}
+	j.Recompute()
+	if j.ColDefs[3].Formula != nil || j.Getf(0, 3) != 10 {
+		t.Errorf("join_test: expected the computed value 10 without a formula, found %v\n", j.Getf(0, 3))
+	}
+}
@@ -0,0 +1,380 @@
+package gotable
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// JSONTable struct used to prepare table in json version
+type JSONTable struct {
+	*Table
+}
+
+// cellTypeNames are the names used for cell types in json and struct tags
+var cellTypeNames = map[int]string{
+	CELLINT:      "int",
+	CELLFLOAT:    "float",
+	CELLSTRING:   "string",
+	CELLDATE:     "date",
+	CELLDATETIME: "datetime",
+	CELLNULL:     "null",
+	CELLDECIMAL:  "decimal",
+}
+
+// cellTypeByName returns the cell type with the supplied name, or 0
+func cellTypeByName(name string) int {
+	for k, v := range cellTypeNames {
+		if v == name {
+			return k
+		}
+	}
+	return 0
+}
+
+// jsonTable is the json form of a Table
+type jsonTable struct {
+	Title       string                       `json:"title,omitempty"`
+	Section1    string                       `json:"section1,omitempty"`
+	Section2    string                       `json:"section2,omitempty"`
+	Section3    string                       `json:"section3,omitempty"`
+	DateFmt     string                       `json:"dateFmt"`
+	DateTimeFmt string                       `json:"dateTimeFmt"`
+	Location    string                       `json:"location,omitempty"`
+	NullText    string                       `json:"nullText,omitempty"`
+	Columns     []jsonColumn                 `json:"columns"`
+	Rows        [][]json.RawMessage          `json:"rows"`
+	Rowsets     [][]int                      `json:"rowsets,omitempty"`
+	LineAfter   []int                        `json:"lineAfter,omitempty"`
+	LineBefore  []int                        `json:"lineBefore,omitempty"`
+	CSS         map[string]map[string]string `json:"css,omitempty"`
+}
+
+// jsonColumn is the json form of a ColumnDef
+type jsonColumn struct {
+	Title     string        `json:"title"`
+	Type      string        `json:"type"`
+	Width     int           `json:"width"`
+	Justify   string        `json:"justify"`
+	Decimals  int           `json:"decimals"`
+	HTMLWidth int           `json:"htmlWidth"`
+	Currency  string        `json:"currency,omitempty"`
+	Rounding  int           `json:"rounding,omitempty"`
+	NullText  string        `json:"nullText,omitempty"`
+	NumFmt    *NumberFormat `json:"numberFormat,omitempty"`
+	DateFmt   string        `json:"dateFmt,omitempty"`
+	Location  string        `json:"location,omitempty"`
+}
+
+// jsonCell is the json form of a cell whose type is not the type of its column
+type jsonCell struct {
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// cellJSON returns the json value of c without its type
+func cellJSON(c *Cell) (json.RawMessage, error) {
+	var v interface{}
+	switch c.Type {
+	case CELLINT:
+		v = c.Ival
+	case CELLFLOAT:
+		v = c.Fval
+		if math.IsNaN(c.Fval) || math.IsInf(c.Fval, 0) {
+			v = strconv.FormatFloat(c.Fval, 'g', -1, 64) // NaN, +Inf or -Inf
+		}
+	case CELLSTRING:
+		v = c.Sval
+	case CELLDECIMAL:
+		v = c.Mval.String()
+	case CELLDATE, CELLDATETIME:
+		v = c.Dval.Format(time.RFC3339Nano)
+	default:
+		return nil, nil
+	}
+	return json.Marshal(v)
+}
+
+// parseCellJSON returns the cell of type celltype whose value is b
+func parseCellJSON(b json.RawMessage, celltype int) (Cell, error) {
+	c := Cell{Type: celltype}
+	var err error
+	switch celltype {
+	case CELLINT:
+		err = json.Unmarshal(b, &c.Ival)
+	case CELLFLOAT:
+		var s string
+		if json.Unmarshal(b, &s) == nil {
+			c.Fval, err = strconv.ParseFloat(s, 64)
+			break
+		}
+		err = json.Unmarshal(b, &c.Fval)
+	case CELLSTRING:
+		err = json.Unmarshal(b, &c.Sval)
+	case CELLNULL:
+	case CELLDECIMAL, CELLDATE, CELLDATETIME:
+		var s string
+		if err = json.Unmarshal(b, &s); err != nil {
+			break
+		}
+		switch celltype {
+		case CELLDECIMAL:
+			c.Mval, err = ParseDecimal(s)
+		default:
+			c.Dval, err = time.Parse(time.RFC3339Nano, s)
+		}
+	default:
+		err = fmt.Errorf("unknown cell type %d", celltype)
+	}
+	return c, err
+}
+
+// jsonCSSKey returns css key k with the row identity of a cell key replaced by
+// the row index, or "" if the row no longer exists
+func (t *Table) jsonCSSKey(k string) string {
+	if !strings.HasPrefix(k, `row:`) || !strings.Contains(k, `-col:`) {
+		return k
+	}
+	i := strings.Index(k, `-col:`)
+	id, err := strconv.Atoi(k[len(`row:`):i])
+	if err != nil {
+		return k
+	}
+	row := t.FindRow(id)
+	if row < 0 {
+		return ""
+	}
+	return `row:` + strconv.Itoa(row) + k[i:]
+}
+
+func (jt *JSONTable) writeTableOutput(w io.Writer) error {
+	t := jt.Table
+	j := jsonTable{
+		Title: t.Title, Section1: t.Section1, Section2: t.Section2, Section3: t.Section3,
+		DateFmt: t.DateFmt, DateTimeFmt: t.DateTimeFmt, NullText: t.nullText,
+		LineAfter: t.LineAfter, LineBefore: t.LineBefore,
+		Location: locationName(t.Location), Rows: [][]json.RawMessage{},
+	}
+	for i := 0; i < len(t.ColDefs); i++ {
+		cd := &t.ColDefs[i]
+		justify := "left"
+		if cd.Justify == COLJUSTIFYRIGHT {
+			justify = "right"
+		}
+		j.Columns = append(j.Columns, jsonColumn{
+			Title: cd.ColTitle, Type: cellTypeNames[cd.CellType], Width: cd.Width, Justify: justify,
+			Decimals: cd.Fdecimals, HTMLWidth: cd.HTMLWidth, Currency: cd.Currency, Rounding: cd.Rounding,
+			NullText: cd.NullText, NumFmt: cd.NumFmt, DateFmt: cd.DateFmt, Location: locationName(cd.Location),
+		})
+	}
+
+	for row := 0; row < len(t.Row); row++ {
+		var cells []json.RawMessage
+		for col := 0; col < len(t.Row[row].Col); col++ {
+			c := &t.Row[row].Col[col]
+			b, err := cellJSON(c)
+			if err != nil {
+				return fmt.Errorf("JSONprintTable: row %d, col %d: %s", row, col, err.Error())
+			}
+			switch {
+			case c.Type == 0:
+				b = json.RawMessage(`null`)
+			case c.Type != t.ColDefs[col].CellType:
+				if b, err = json.Marshal(jsonCell{Type: cellTypeNames[c.Type], Value: b}); err != nil {
+					return err
+				}
+			}
+			cells = append(cells, b)
+		}
+		j.Rows = append(j.Rows, cells)
+	}
+
+	for i := 0; i < len(t.RS); i++ {
+		j.Rowsets = append(j.Rowsets, t.RS[i].R)
+	}
+	for k, props := range t.CSS {
+		key := t.jsonCSSKey(k)
+		if key == "" || len(props) == 0 {
+			continue
+		}
+		if j.CSS == nil {
+			j.CSS = map[string]map[string]string{}
+		}
+		m := map[string]string{}
+		for name, p := range props {
+			m[name] = p.Value
+		}
+		j.CSS[key] = m
+	}
+
+	b, err := json.MarshalIndent(&j, "", "  ")
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+	_, err = w.Write(b)
+	return err
+}
+
+// locationName returns the name of loc, or "" if loc is nil
+func locationName(loc *time.Location) string {
+	if loc == nil {
+		return ""
+	}
+	return loc.String()
+}
+
+// loadLocation returns the time zone called name, or nil if name is ""
+func loadLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return nil, nil
+	}
+	return time.LoadLocation(name)
+}
+
+// tableFromJSON returns the table described by j
+func tableFromJSON(j *jsonTable) (*Table, error) {
+	var t Table
+	t.Init()
+	t.Title, t.Section1, t.Section2, t.Section3 = j.Title, j.Section1, j.Section2, j.Section3
+	if j.DateFmt != "" {
+		t.DateFmt = j.DateFmt
+	}
+	if j.DateTimeFmt != "" {
+		t.DateTimeFmt = j.DateTimeFmt
+	}
+	t.nullText = j.NullText
+	var err error
+	if t.Location, err = loadLocation(j.Location); err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < len(j.Columns); i++ {
+		jc := &j.Columns[i]
+		celltype := cellTypeByName(jc.Type)
+		if celltype == 0 {
+			return nil, fmt.Errorf("column %d: unknown type %q", i, jc.Type)
+		}
+		justify := COLJUSTIFYLEFT
+		if jc.Justify == "right" {
+			justify = COLJUSTIFYRIGHT
+		}
+		t.AddColumn(jc.Title, jc.Width, celltype, justify)
+		cd := &t.ColDefs[i]
+		cd.Fdecimals, cd.HTMLWidth = jc.Decimals, jc.HTMLWidth
+		cd.Currency, cd.Rounding, cd.NullText = jc.Currency, jc.Rounding, jc.NullText
+		cd.NumFmt, cd.DateFmt = jc.NumFmt, jc.DateFmt
+		if cd.Location, err = loadLocation(jc.Location); err != nil {
+			return nil, fmt.Errorf("column %d: %s", i, err.Error())
+		}
+	}
+
+	for row := 0; row < len(j.Rows); row++ {
+		t.AddRow()
+		if len(j.Rows[row]) > len(t.ColDefs) {
+			return nil, fmt.Errorf("row %d: too many cells", row)
+		}
+		for col := 0; col < len(j.Rows[row]); col++ {
+			b := j.Rows[row][col]
+			if len(b) == 0 || string(b) == "null" {
+				continue
+			}
+			celltype := t.ColDefs[col].CellType
+			if b[0] == '{' {
+				var jc jsonCell
+				if err := json.Unmarshal(b, &jc); err != nil {
+					return nil, fmt.Errorf("row %d, col %d: %s", row, col, err.Error())
+				}
+				if celltype = cellTypeByName(jc.Type); celltype == 0 {
+					return nil, fmt.Errorf("row %d, col %d: unknown type %q", row, col, jc.Type)
+				}
+				b = jc.Value
+			}
+			c, err := parseCellJSON(b, celltype)
+			if err != nil {
+				return nil, fmt.Errorf("row %d, col %d: %s", row, col, err.Error())
+			}
+			t.Row[row].Col[col] = c
+		}
+	}
+
+	for i := 0; i < len(j.Rowsets); i++ {
+		rsid := t.CreateRowset()
+		for k := 0; k < len(j.Rowsets[i]); k++ {
+			t.AppendToRowset(rsid, j.Rowsets[i][k])
+		}
+	}
+	t.LineAfter, t.LineBefore = j.LineAfter, j.LineBefore
+
+	for k, props := range j.CSS {
+		var row, col int
+		if n, _ := fmt.Sscanf(k, "row:%d-col:%d", &row, &col); n == 2 {
+			if row < 0 || row >= len(t.Row) {
+				continue
+			}
+			k = t.getCSSMapKeyForCell(row, col)
+		}
+		m := map[string]*CSSProperty{}
+		for name, value := range props {
+			m[name] = &CSSProperty{Name: name, Value: value}
+		}
+		t.CSS[k] = m
+	}
+	return &t, nil
+}
+
+// ReadJSON creates a table from json written by JSONprintTable
+func ReadJSON(r io.Reader) (*Table, error) {
+	var j jsonTable
+	if err := json.NewDecoder(r).Decode(&j); err != nil {
+		return nil, fmt.Errorf("ReadJSON: %s", err.Error())
+	}
+	t, err := tableFromJSON(&j)
+	if err != nil {
+		return nil, fmt.Errorf("ReadJSON: %s", err.Error())
+	}
+	return t, nil
+}
+
+// ReadMultiTableJSON creates tables from json written by MultiTableJSONPrint
+func ReadMultiTableJSON(r io.Reader) ([]Table, error) {
+	var a []jsonTable
+	if err := json.NewDecoder(r).Decode(&a); err != nil {
+		return nil, fmt.Errorf("ReadMultiTableJSON: %s", err.Error())
+	}
+	var m []Table
+	for i := 0; i < len(a); i++ {
+		t, err := tableFromJSON(&a[i])
+		if err != nil {
+			return nil, fmt.Errorf("ReadMultiTableJSON: table %d: %s", i, err.Error())
+		}
+		m = append(m, *t)
+	}
+	return m, nil
+}
+
+// MultiTableJSONPrint writes the tables in m to w as a json array
+func MultiTableJSONPrint(m []Table, w io.Writer) error {
+	funcname := "MultiTableJSONPrint"
+
+	if _, err := io.WriteString(w, "[\n"); err != nil {
+		return err
+	}
+	for i := 0; i < len(m); i++ {
+		if i > 0 {
+			if _, err := io.WriteString(w, ",\n"); err != nil {
+				return err
+			}
+		}
+		if err := m[i].JSONprintTable(w); err != nil {
+			errorLog(funcname, ": Error while getting table output, title: ", m[i].Title, ", err: ", err.Error())
+			return err
+		}
+	}
+	_, err := io.WriteString(w, "]\n")
+	return err
+}
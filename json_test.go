@@ -0,0 +1,152 @@
+package gotable
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJSON(t *testing.T) {
+	var tbl Table
+	tbl.Init()
+	tbl.SetTitle("Rent Roll")
+	tbl.SetSection1("Building A")
+	tbl.SetSection3("confidential")
+	tbl.AddColumn("Unit", 6, CELLSTRING, COLJUSTIFYLEFT)
+	tbl.AddColumn("Beds", 4, CELLINT, COLJUSTIFYRIGHT)
+	tbl.AddColumn("Rate", 8, CELLFLOAT, COLJUSTIFYRIGHT)
+	tbl.AddColumn("Rent", 12, CELLDECIMAL, COLJUSTIFYRIGHT)
+	tbl.AddColumn("Move In", 10, CELLDATE, COLJUSTIFYLEFT)
+	tbl.AddColumn("Updated", 25, CELLDATETIME, COLJUSTIFYLEFT)
+	tbl.SetColDecimal(3, 2, "$", ROUNDHALFEVEN)
+	tbl.SetColNullText(2, "n/a")
+
+	d := time.Date(2017, time.June, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 4; i++ {
+		tbl.AddRow()
+		tbl.Puts(-1, 0, "10"+string(rune('1'+i)))
+		tbl.Puti(-1, 1, int64(i+1))
+		tbl.Putf(-1, 2, 1.25*float64(i))
+		tbl.Putm(-1, 3, NewDecimal(int64(100050+i), 2))
+		tbl.Putd(-1, 4, d.AddDate(0, i, 0))
+		tbl.Putdt(-1, 5, d.Add(time.Duration(i)*time.Hour))
+	}
+	tbl.DeleteRow(0) // row identities no longer match row indeces
+	tbl.PutNull(1, 2)
+	tbl.AddRow()
+	tbl.Puts(-1, 0, "Total")
+	tbl.Puts(-1, 1, "n/a") // a cell whose type is not its column's type
+	tbl.Put(-1, 3, tbl.Sum(3))
+	tbl.AddLineBefore(3)
+	tbl.AddLineAfter(3)
+	rs := tbl.CreateRowset()
+	tbl.AppendToRowset(rs, 0)
+	tbl.AppendToRowset(rs, 2)
+	tbl.SetTitleCSS([]*CSSProperty{{Name: "color", Value: "red"}})
+	tbl.SetCellCSS(2, 3, []*CSSProperty{{Name: "font-weight", Value: "bold"}})
+
+	var b bytes.Buffer
+	if err := tbl.JSONprintTable(&b); err != nil {
+		t.Errorf("json_test: JSONprintTable: %s\n", err.Error())
+		return
+	}
+	out := b.String()
+
+	// the json is easy to use without gotable
+	var j map[string]interface{}
+	if err := json.Unmarshal([]byte(out), &j); err != nil {
+		t.Errorf("json_test: output is not valid json: %s\n", err.Error())
+		return
+	}
+	rows := j["rows"].([]interface{})
+	if row := rows[0].([]interface{}); row[0] != "102" || row[1] != 2.0 || row[3] != "1000.51" || row[4] != "2017-07-01T00:00:00Z" {
+		t.Errorf("json_test: unexpected first row: %#v\n", row)
+	}
+	if !strings.Contains(out, `"row:2-col:3"`) {
+		t.Errorf("json_test: css keys should use row indeces:\n%s\n", out)
+	}
+
+	r, err := ReadJSON(strings.NewReader(out))
+	if err != nil {
+		t.Errorf("json_test: ReadJSON: %s\n", err.Error())
+		return
+	}
+	for i := 0; i < tbl.RowCount(); i++ {
+		for c := 0; c < tbl.ColCount(); c++ {
+			a, b := tbl.Get(i, c), r.Get(i, c)
+			if a.Type != b.Type || compareCells(&a, &b) != 0 {
+				t.Errorf("json_test: cell %d,%d: expected %#v, found %#v\n", i, c, a, b)
+			}
+		}
+	}
+	if !reflect.DeepEqual(r.RS, tbl.RS) || !reflect.DeepEqual(r.LineAfter, tbl.LineAfter) || !reflect.DeepEqual(r.LineBefore, tbl.LineBefore) {
+		t.Errorf("json_test: rowsets or line markers differ\n")
+	}
+
+	// the read table prints the same as the original
+	var s1, s2 bytes.Buffer
+	tbl.FprintTable(&s1)
+	r.FprintTable(&s2)
+	if s1.String() != s2.String() {
+		t.Errorf("json_test: text output differs:\n%s\n%s\n", s1.String(), s2.String())
+	}
+	s1.Reset()
+	s2.Reset()
+	tbl.HTMLprintTable(&s1)
+	r.HTMLprintTable(&s2)
+	if s1.String() != s2.String() {
+		t.Errorf("json_test: html output differs:\n%s\n%s\n", s1.String(), s2.String())
+	}
+
+	// several tables
+	b.Reset()
+	if err := MultiTableJSONPrint([]Table{tbl, *r}, &b); err != nil {
+		t.Errorf("json_test: MultiTableJSONPrint: %s\n", err.Error())
+	}
+	m, err := ReadMultiTableJSON(&b)
+	if err != nil || len(m) != 2 || m[1].Title != "Rent Roll" {
+		t.Errorf("json_test: ReadMultiTableJSON: expected 2 tables, found %d (%v)\n", len(m), err)
+	}
+
+	if _, err := ReadJSON(strings.NewReader(`{"columns":[{"title":"A","type":"int"}],"rows":[["x"]]}`)); err == nil {
+		t.Errorf("json_test: expected an error for a string in an int column\n")
+	}
+}
+
+func TestJSONDatesAndFloats(t *testing.T) {
+	var tbl Table
+	tbl.Init()
+	tbl.AddColumn("Due", 10, CELLDATE, COLJUSTIFYLEFT)
+	tbl.AddColumn("Rate", 10, CELLFLOAT, COLJUSTIFYRIGHT)
+	due := time.Date(2017, time.July, 1, 15, 30, 0, 0, time.FixedZone("", -4*3600))
+	floats := []float64{math.NaN(), math.Inf(1), math.Inf(-1), 1.5}
+	for i := 0; i < len(floats); i++ {
+		tbl.AddRow()
+		tbl.Putd(-1, 0, due)
+		tbl.Putf(-1, 1, floats[i])
+	}
+
+	var b bytes.Buffer
+	if err := tbl.JSONprintTable(&b); err != nil {
+		t.Errorf("json_test: JSONprintTable: %s\n", err.Error())
+		return
+	}
+	r, err := ReadJSON(&b)
+	if err != nil {
+		t.Errorf("json_test: ReadJSON: %s\n", err.Error())
+		return
+	}
+	for i := 0; i < len(floats); i++ {
+		if d := r.Getd(i, 0); !d.Equal(due) || d.Format(time.RFC3339) != due.Format(time.RFC3339) {
+			t.Errorf("json_test: row %d: expected date %s, found %s\n", i, due, d)
+		}
+		f := r.Getf(i, 1)
+		if f != floats[i] && !(math.IsNaN(f) && math.IsNaN(floats[i])) {
+			t.Errorf("json_test: row %d: expected %g, found %g\n", i, floats[i], f)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package gotable
+
+import (
+	"bytes"
+	"io"
+	"strings"
+)
+
+// MarkdownTable struct used to prepare table in markdown (GitHub flavored) version
+type MarkdownTable struct {
+	*Table
+	buf bytes.Buffer
+}
+
+// mdEscaper escapes the characters that would break a markdown table cell. A
+// backslash is escaped too, so that one at the end of a cell or before a pipe
+// does not escape the pipe that follows it.
+var mdEscaper = strings.NewReplacer(`\`, `\\`, `|`, `\|`, "\r\n", `<br>`, "\n", `<br>`, "\r", `<br>`)
+
+// mdCell returns s escaped for use in a markdown table cell
+func mdCell(s string) string {
+	return mdEscaper.Replace(strings.TrimSpace(s))
+}
+
+func (mt *MarkdownTable) writeTableOutput(w io.Writer) error {
+
+	// vars
+	var (
+		err error
+	)
+
+	// append title
+	mt.buf.WriteString(mt.formatTitle())
+
+	// append section 1
+	mt.buf.WriteString(mt.formatSection1())
+
+	// append section 2
+	mt.buf.WriteString(mt.formatSection2())
+
+	// append section 3
+	mt.buf.WriteString(mt.formatSection3())
+
+	// append headers
+	if headerStr, err := mt.formatHeaders(); err != nil {
+		mt.buf.WriteString(stringln(err.Error()))
+	} else {
+		// append rows
+		if rowsStr, err := mt.formatRows(); err != nil {
+			mt.buf.WriteString(stringln(err.Error()))
+		} else {
+			// if rows exist, then only show headers
+			mt.buf.WriteString(headerStr)
+			mt.buf.WriteString(rowsStr)
+		}
+	}
+
+	// write output to passed io.Writer interface object
+	_, err = w.Write(mt.buf.Bytes())
+	return err
+}
+
+// mdHeading returns s as a markdown heading of the supplied level
+func mdHeading(s string, level int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if s == "" {
+		return ""
+	}
+	return mkstr(level, '#') + " " + s + "\n\n"
+}
+
+// mdParagraph returns s as a markdown paragraph, keeping its line breaks
+func mdParagraph(s string) string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return ""
+	}
+	return strings.Replace(s, "\n", "  \n", -1) + "\n\n"
+}
+
+func (mt *MarkdownTable) formatTitle() string {
+	return mdHeading(mt.Table.GetTitle(), 2)
+}
+
+func (mt *MarkdownTable) formatSection1() string {
+	return mdHeading(mt.Table.GetSection1(), 3)
+}
+
+func (mt *MarkdownTable) formatSection2() string {
+	return mdParagraph(mt.Table.GetSection2())
+}
+
+func (mt *MarkdownTable) formatSection3() string {
+	return mdParagraph(mt.Table.GetSection3())
+}
+
+// formatHeaders returns the header row of the table and the delimiter row
+// below it, which holds the alignment of each column
+func (mt *MarkdownTable) formatHeaders() (string, error) {
+
+	// check for blank headers
+	blankHdrsErr := mt.Table.HasHeaders()
+	if blankHdrsErr != nil {
+		return "", blankHdrsErr
+	}
+
+	var s bytes.Buffer
+	s.WriteString("|")
+	for i := 0; i < len(mt.Table.ColDefs); i++ {
+		s.WriteString(" " + mdCell(mt.Table.ColDefs[i].ColTitle) + " |")
+	}
+	s.WriteString("\n|")
+	for i := 0; i < len(mt.Table.ColDefs); i++ {
+		if mt.Table.ColDefs[i].Justify == COLJUSTIFYRIGHT {
+			s.WriteString(" ---: |")
+		} else {
+			s.WriteString(" :--- |")
+		}
+	}
+	s.WriteString("\n")
+	return s.String(), nil
+}
+
+func (mt *MarkdownTable) formatRows() (string, error) {
+
+	// check for empty data table
+	blankDataErr := mt.Table.HasData()
+	if blankDataErr != nil {
+		return "", blankDataErr
+	}
+
+	var rowsOut bytes.Buffer
+	for i := 0; i < mt.Table.RowCount(); i++ {
+		// for valid row, we will never get an error
+		s, _ := mt.formatRow(i)
+		rowsOut.WriteString(s)
+	}
+
+	return rowsOut.String(), nil
+}
+
+// formatRow returns one row of the table. Markdown tables have no horizontal
+// lines, so a line between two rows is shown as a row of dashes. Lines above
+// the first row and below the last row are left out, and a line that is both
+// after a row and before the next one is shown once.
+func (mt *MarkdownTable) formatRow(row int) (string, error) {
+	var s bytes.Buffer
+
+	if row > 0 && mt.Table.hasLineBefore(row) && !mt.Table.hasLineAfter(row-1) {
+		s.WriteString(mt.sprintLine())
+	}
+
+	s.WriteString("|")
+	for col := 0; col < len(mt.Table.Row[row].Col); col++ {
+		s.WriteString(" " + mdCell(mt.Table.cellText(col, &mt.Table.Row[row].Col[col])) + " |")
+	}
+	s.WriteString("\n")
+
+	if row < len(mt.Table.Row)-1 && mt.Table.hasLineAfter(row) {
+		s.WriteString(mt.sprintLine())
+	}
+	return s.String(), nil
+}
+
+// sprintLine returns a row of dashes, used where the table has a line
+func (mt *MarkdownTable) sprintLine() string {
+	return "|" + strings.Repeat(" --- |", len(mt.Table.ColDefs)) + "\n"
+}
+
+// MultiTableMarkdownPrint writes markdown output from each table to w io.Writer
+func MultiTableMarkdownPrint(m []Table, w io.Writer) error {
+	funcname := "MultiTableMarkdownPrint"
+
+	for i := 0; i < len(m); i++ {
+		temp := bytes.Buffer{}
+		err := m[i].MarkdownprintTable(&temp)
+		if err != nil {
+			errorLog(funcname, ": Error while getting table output, title: ", m[i].Title, ", err: ", err.Error())
+			return err
+		}
+		temp.WriteByte('\n')
+		w.Write(temp.Bytes())
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package gotable
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestMarkdown(t *testing.T) {
+	var tbl Table
+	tbl.Init()
+	tbl.SetTitle("Rent Roll\n")
+	tbl.SetSection1("Building A")
+	tbl.SetSection2("March 2018\nAll units")
+	tbl.AddColumn("Unit", 6, CELLSTRING, COLJUSTIFYLEFT)
+	tbl.AddColumn("Tenant", 20, CELLSTRING, COLJUSTIFYLEFT)
+	tbl.AddColumn("Rent", 10, CELLFLOAT, COLJUSTIFYRIGHT)
+	tbl.AddColumn("Deposit", 10, CELLDECIMAL, COLJUSTIFYRIGHT)
+	tbl.AddColumn("Move In", 10, CELLDATE, COLJUSTIFYLEFT)
+	tbl.SetColDecimal(3, 2, "$", ROUNDHALFUP)
+	tbl.SetNullText("n/a")
+
+	d := time.Date(2017, time.June, 1, 0, 0, 0, 0, time.UTC)
+	tbl.AddRow()
+	tbl.Puts(-1, 0, "101")
+	tbl.Puts(-1, 1, "Smith | Jones")
+	tbl.Putf(-1, 2, 1250.5)
+	tbl.Putm(-1, 3, NewDecimal(50000, 2))
+	tbl.Putd(-1, 4, d)
+	tbl.AddRow()
+	tbl.Puts(-1, 0, "102")
+	tbl.Puts(-1, 1, "Acme")
+	tbl.Putf(-1, 2, 900)
+	tbl.PutNull(-1, 3)
+	tbl.AddRow()
+	tbl.Puts(-1, 0, "Total")
+	tbl.Put(-1, 2, tbl.Sum(2))
+	tbl.Put(-1, 3, tbl.Sum(3))
+	tbl.AddLineBefore(2)
+	tbl.AddLineAfter(2)
+	tbl.AddLineAfter(0) // and before row 1, shown once
+	tbl.AddLineBefore(1)
+
+	var b bytes.Buffer
+	if err := tbl.MarkdownprintTable(&b); err != nil {
+		t.Errorf("markdown_test: MarkdownprintTable: %s\n", err.Error())
+	}
+	expect := "## Rent Roll\n\n" +
+		"### Building A\n\n" +
+		"March 2018  \nAll units\n\n" +
+		"| Unit | Tenant | Rent | Deposit | Move In |\n" +
+		"| :--- | :--- | ---: | ---: | :--- |\n" +
+		"| 101 | Smith \\| Jones | 1,250.50 | $500.00 | 06/01/2017 |\n" +
+		"| --- | --- | --- | --- | --- |\n" +
+		"| 102 | Acme | 900.00 | n/a |  |\n" +
+		"| --- | --- | --- | --- | --- |\n" +
+		"| Total |  | 2,150.50 | $500.00 |  |\n"
+	if b.String() != expect {
+		t.Errorf("markdown_test: expected:\n%s\nfound:\n%s\n", expect, b.String())
+	}
+
+	if s := mdCell(`a\`); s != `a\\` {
+		t.Errorf("markdown_test: mdCell: a trailing backslash: found %q\n", s)
+	}
+	if s := mdCell(`a\|b`); s != `a\\\|b` {
+		t.Errorf("markdown_test: mdCell: a backslash before a pipe: found %q\n", s)
+	}
+	if s := mdCell(" a|b\nc "); s != `a\|b<br>c` {
+		t.Errorf("markdown_test: mdCell: found %q\n", s)
+	}
+
+	// an empty table says so
+	var e Table
+	e.Init()
+	b.Reset()
+	e.MarkdownprintTable(&b)
+	if b.Len() == 0 {
+		t.Errorf("markdown_test: expected a message for a table without columns\n")
+	}
+
+	b.Reset()
+	if err := MultiTableMarkdownPrint([]Table{tbl, tbl}, &b); err != nil || b.Len() != 2*(len(expect)+1) {
+		t.Errorf("markdown_test: MultiTableMarkdownPrint: unexpected output:\n%s\n", b.String())
+	}
+}
@@ -0,0 +1,69 @@
+package gotable
+
+// PutNull marks the Cell at row,col as null, meaning it has no value. This is
+// different from a value of 0 or "". Null cells are printed using the null
+// text of the column, see SetColNullText. If row < 0 then row is set to the
+// last row of the table. If row or col is out of bounds the return value is
+// false. Otherwise, the return value is true.
+func (t *Table) PutNull(row, col int) bool {
+	if row >= len(t.Row) || col >= len(t.ColDefs) {
+		return false
+	}
+	if row < 0 {
+		row = len(t.Row) - 1
+	}
+	t.Row[row].Col[col] = Cell{Type: CELLNULL}
+	return true
+}
+
+// IsNull reports whether the Cell at row,col has no value, either because it
+// was never set or because it was set with PutNull. If the supplied row or col
+// is outside the table's boundaries, then true is returned.
+func (t *Table) IsNull(row, col int) bool {
+	c := t.Get(row, col)
+	return isNullCell(&c)
+}
+
+// SetNullText sets the text printed for null cells in the columns that have no
+// null text of their own. The default is to leave them blank.
+func (t *Table) SetNullText(s string) {
+	t.nullText = s
+}
+
+// SetColNullText sets the text printed for null cells in column col, for
+// example "n/a" or "—". It is used by the text, html and pdf output. Cells that
+// were never set are always blank.
+func (t *Table) SetColNullText(col int, s string) error {
+	if err := t.HasValidColumn(col); err != nil {
+		return err
+	}
+	t.ColDefs[col].NullText = s
+	return nil
+}
+
+// SetCSVNullText sets the value written for null cells in csv output, for
+// example "NULL" or "\N". The default is an empty field.
+func (t *Table) SetCSVNullText(s string) {
+	t.csvNullText = s
+}
+
+// colNullText returns the text printed for null cells in column col
+func (t *Table) colNullText(col int) string {
+	if t.ColDefs[col].NullText != "" {
+		return t.ColDefs[col].NullText
+	}
+	return t.nullText
+}
+
+// sprintNull returns the null text of column col, justified to the width of
+// the column
+func (t *Table) sprintNull(col int) string {
+	return t.sprintText(col, t.colNullText(col))
+}
+
+// sprintText returns s fitted to the width of column col for text output,
+// justified as the column
+func (t *Table) sprintText(col int, s string) string {
+	cd := &t.ColDefs[col]
+	return padToWidth(s, cd.Width, cd.Justify == COLJUSTIFYLEFT)
+}
@@ -0,0 +1,90 @@
+package gotable
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestNulls(t *testing.T) {
+	var tbl Table
+	tbl.Init()
+	tbl.AddColumn("Name", 10, CELLSTRING, COLJUSTIFYLEFT)
+	tbl.AddColumn("Score", 8, CELLINT, COLJUSTIFYRIGHT)
+	tbl.AddColumn("Rate", 8, CELLFLOAT, COLJUSTIFYRIGHT)
+
+	tbl.AddRow()
+	tbl.Puts(-1, 0, "zero")
+	tbl.Puti(-1, 1, 0)
+	tbl.Putf(-1, 2, 0)
+	tbl.AddRow()
+	tbl.Puts(-1, 0, "null")
+	tbl.PutNull(-1, 1)
+	tbl.PutNull(-1, 2)
+	tbl.AddRow()
+	tbl.Puts(-1, 0, "ten")
+	tbl.Puti(-1, 1, 10)
+	tbl.Putf(-1, 2, 1.5)
+
+	if tbl.IsNull(0, 1) || !tbl.IsNull(1, 1) || !tbl.IsNull(5, 1) {
+		t.Errorf("null_test: IsNull is wrong\n")
+	}
+
+	if c := tbl.Count(1); c.Ival != 2 {
+		t.Errorf("null_test: Count: expected 2, found %d\n", c.Ival)
+	}
+	if c := tbl.Aggregate(1, AggCountNull); c.Ival != 1 {
+		t.Errorf("null_test: AggCountNull: expected 1, found %d\n", c.Ival)
+	}
+	if c := tbl.Aggregate(1, AggCountAll); c.Ival != 3 {
+		t.Errorf("null_test: AggCountAll: expected 3, found %d\n", c.Ival)
+	}
+	if c := tbl.Avg(1); c.Fval != 5 {
+		t.Errorf("null_test: Avg: expected 5, found %f\n", c.Fval)
+	}
+	if c := tbl.Aggregate(1, NullsAsZero(AggAvg)); c.Fval < 3.33 || c.Fval > 3.34 {
+		t.Errorf("null_test: NullsAsZero(AggAvg): expected 3.33, found %f\n", c.Fval)
+	}
+	if c := tbl.Min(1); c.Ival != 0 || c.Type != CELLINT {
+		t.Errorf("null_test: Min: expected 0, found %#v\n", c)
+	}
+
+	// placeholders
+	tbl.SetNullText("n/a")
+	tbl.SetColNullText(2, "—")
+	s, _ := tbl.SprintTable()
+	if !strings.Contains(s, "null             n/a         —") {
+		t.Errorf("null_test: text output does not show null text:\n%s\n", s)
+	}
+	if !strings.Contains(s, "zero               0      0.00") {
+		t.Errorf("null_test: text output does not show zeros:\n%s\n", s)
+	}
+
+	var b bytes.Buffer
+	tbl.CSVprintTable(&b)
+	if !strings.Contains(b.String(), "null,,\n") {
+		t.Errorf("null_test: csv: expected empty null fields:\n%s\n", b.String())
+	}
+	tbl.SetCSVNullText(`\N`)
+	b.Reset()
+	tbl.CSVprintTable(&b)
+	if !strings.Contains(b.String(), `null,\N,\N`) {
+		t.Errorf("null_test: csv: expected \\N null fields:\n%s\n", b.String())
+	}
+
+	b.Reset()
+	tbl.HTMLprintTable(&b)
+	if !strings.Contains(b.String(), "n/a") || !strings.Contains(b.String(), "—") {
+		t.Errorf("null_test: html output does not show null text:\n%s\n", b.String())
+	}
+
+	// sorting puts nulls last by default, or first if asked
+	tbl.SortBy(0, 2, SortKey{Col: 1})
+	if tbl.Gets(2, 0) != "null" {
+		t.Errorf("null_test: expected null last, found %s\n", tbl.Gets(2, 0))
+	}
+	tbl.SortBy(0, 2, SortKey{Col: 1, Desc: true, NullsFirst: true})
+	if tbl.Gets(0, 0) != "null" || tbl.Gets(1, 0) != "ten" {
+		t.Errorf("null_test: expected null first, found %s, %s\n", tbl.Gets(0, 0), tbl.Gets(1, 0))
+	}
+}
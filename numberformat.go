@@ -0,0 +1,179 @@
+package gotable
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// negative number styles
+const (
+	NEGMINUS  = 0 // -1,234.50. The default
+	NEGPARENS = 1 // (1,234.50)
+	NEGCR     = 2 // 1,234.50 CR
+)
+
+// NumberFormat describes how the numbers of a column are printed in text, CSV,
+// HTML, markdown and PDF output. The number of digits after the decimal mark
+// is the Fdecimals of the column for CELLFLOAT values and the scale of the
+// value for CELLDECIMAL values. CELLINT values are printed without decimals.
+type NumberFormat struct {
+	Grouping    string `json:"grouping,omitempty"`    // thousands separator, "" for none
+	DecimalMark string `json:"decimalMark,omitempty"` // "" is the same as "."
+	Negative    int    `json:"negative,omitempty"`    // one of NEGMINUS, NEGPARENS or NEGCR
+	ZeroText    string `json:"zeroText,omitempty"`    // printed instead of values that are zero, "" prints the number
+}
+
+// defaultNumberFormat is used for CELLFLOAT and CELLDECIMAL columns without a
+// NumberFormat. It prints 1234.5 as 1,234.50.
+var defaultNumberFormat = NumberFormat{Grouping: ",", DecimalMark: "."}
+
+// numberFormats are the locale presets returned by LocaleNumberFormat
+var numberFormats = map[string]NumberFormat{
+	"en-US": {Grouping: ",", DecimalMark: "."},
+	"en-GB": {Grouping: ",", DecimalMark: "."},
+	"ja-JP": {Grouping: ",", DecimalMark: "."},
+	"de-DE": {Grouping: ".", DecimalMark: ","},
+	"es-ES": {Grouping: ".", DecimalMark: ","},
+	"it-IT": {Grouping: ".", DecimalMark: ","},
+	"nl-NL": {Grouping: ".", DecimalMark: ","},
+	"pt-BR": {Grouping: ".", DecimalMark: ","},
+	"fr-FR": {Grouping: "\u202f", DecimalMark: ","}, // narrow no-break space
+	"de-CH": {Grouping: "\u2019", DecimalMark: "."}, // right single quotation mark
+}
+
+// LocaleNumberFormat returns the number format used in locale, e.g. "de-DE"
+// prints 1234.5 as 1.234,50. The result can be changed before it is passed
+// to SetColNumberFormat.
+func LocaleNumberFormat(locale string) (*NumberFormat, error) {
+	nf, ok := numberFormats[locale]
+	if !ok {
+		return nil, fmt.Errorf("LocaleNumberFormat: unknown locale %q", locale)
+	}
+	return &nf, nil
+}
+
+// SetColNumberFormat sets the number format of column col. Passing nil
+// restores the default: "," between thousands and "." before the decimals
+// for CELLFLOAT and CELLDECIMAL values, and no separators for CELLINT values.
+func (t *Table) SetColNumberFormat(col int, nf *NumberFormat) error {
+	if err := t.HasValidColumn(col); err != nil {
+		return err
+	}
+	if nf != nil && (nf.Negative < NEGMINUS || nf.Negative > NEGCR) {
+		return fmt.Errorf("SetColNumberFormat: invalid negative style %d", nf.Negative)
+	}
+	t.ColDefs[col].NumFmt = nf
+	return nil
+}
+
+// SetColDecimals sets the number of digits printed after the decimal mark for
+// the CELLFLOAT values of column col
+func (t *Table) SetColDecimals(col, decimals int) error {
+	if err := t.HasValidColumn(col); err != nil {
+		return err
+	}
+	if decimals < 0 || decimals > 15 {
+		return fmt.Errorf("SetColDecimals: invalid number of decimals %d", decimals)
+	}
+	t.ColDefs[col].Fdecimals = decimals
+	return nil
+}
+
+// numberFormat returns the number format of column col
+func (t *Table) numberFormat(col int) *NumberFormat {
+	if nf := t.ColDefs[col].NumFmt; nf != nil {
+		return nf
+	}
+	return &defaultNumberFormat
+}
+
+// format returns the number made of the digits whole and frac, with the
+// currency symbol in front of it
+func (nf *NumberFormat) format(neg bool, whole, frac, symbol string) string {
+	if nf.ZeroText != "" && strings.Trim(whole+frac, "0") == "" {
+		return nf.ZeroText
+	}
+	if nf.Grouping != "" {
+		var b strings.Builder
+		for i := 0; i < len(whole); i++ {
+			if i > 0 && (len(whole)-i)%3 == 0 {
+				b.WriteString(nf.Grouping)
+			}
+			b.WriteByte(whole[i])
+		}
+		whole = b.String()
+	}
+	s := symbol + whole
+	if len(frac) > 0 {
+		mark := nf.DecimalMark
+		if mark == "" {
+			mark = "."
+		}
+		s += mark + frac
+	}
+	if !neg {
+		return s
+	}
+	switch nf.Negative {
+	case NEGPARENS:
+		return "(" + s + ")"
+	case NEGCR:
+		return s + " CR"
+	}
+	return "-" + s
+}
+
+// formatFloat returns f with the supplied number of decimals. Values are
+// rounded half away from zero and values closer to zero than 1e-9 are zero,
+// so the default format prints the same text as humanize's "#,###.##".
+func (nf *NumberFormat) formatFloat(f float64, decimals int) string {
+	switch {
+	case math.IsNaN(f):
+		return "NaN"
+	case math.IsInf(f, 1):
+		return "Infinity"
+	case math.IsInf(f, -1):
+		return "-Infinity"
+	}
+	if decimals < 0 {
+		decimals = 0
+	}
+	neg := false
+	if f <= -0.000000001 {
+		neg, f = true, -f
+	} else if f < 0.000000001 {
+		f = 0
+	}
+	whole, fraction := math.Modf(f + 0.5/math.Pow10(decimals))
+	frac := ""
+	if decimals > 0 {
+		frac = strconv.FormatInt(int64(fraction*math.Pow10(decimals)), 10)
+		frac = strings.Repeat("0", decimals-len(frac)) + frac
+	}
+	return nf.format(neg, strconv.FormatFloat(whole, 'f', 0, 64), frac, "")
+}
+
+// floatString returns f formatted for column col, without padding
+func (t *Table) floatString(col int, f float64) string {
+	return t.numberFormat(col).formatFloat(f, t.ColDefs[col].Fdecimals)
+}
+
+// intString returns v formatted for column col, without padding
+func (t *Table) intString(col int, v int64) string {
+	nf := t.ColDefs[col].NumFmt
+	if nf == nil {
+		return strconv.FormatInt(v, 10)
+	}
+	digits := strconv.FormatInt(v, 10)
+	return nf.format(v < 0, strings.TrimPrefix(digits, "-"), "", "")
+}
+
+// sprintInt returns v formatted and padded for column col
+func (t *Table) sprintInt(col int, v int64) string {
+	if t.ColDefs[col].NumFmt == nil {
+		return fmt.Sprintf(t.ColDefs[col].Pfmt, v)
+	}
+	return t.sprintText(col, t.intString(col, v))
+}
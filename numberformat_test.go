@@ -0,0 +1,107 @@
+package gotable
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestNumberFormat(t *testing.T) {
+	// the default format matches the text printed before number formats
+	// were added
+	floats := []struct {
+		f      float64
+		expect string
+	}{
+		{0, "0.00"},
+		{1250, "1,250.00"},
+		{-1234.5, "-1,234.50"},
+		{0.125, "0.13"},
+		{0.0000000001, "0.00"},
+		{-0.001, "-0.00"},
+		{1234567.891, "1,234,567.89"},
+		{math.NaN(), "NaN"},
+		{math.Inf(-1), "-Infinity"},
+	}
+	for i := 0; i < len(floats); i++ {
+		if s := defaultNumberFormat.formatFloat(floats[i].f, 2); s != floats[i].expect {
+			t.Errorf("numberformat_test: formatFloat(%g): expected %q, found %q\n", floats[i].f, floats[i].expect, s)
+		}
+	}
+
+	de, err := LocaleNumberFormat("de-DE")
+	if err != nil {
+		t.Errorf("numberformat_test: LocaleNumberFormat: %s\n", err.Error())
+		return
+	}
+	fr, _ := LocaleNumberFormat("fr-FR")
+	if _, err := LocaleNumberFormat("xx-XX"); err == nil {
+		t.Errorf("numberformat_test: expected an error for an unknown locale\n")
+	}
+	fr.Negative = NEGPARENS
+	cr := &NumberFormat{Grouping: ",", Negative: NEGCR, ZeroText: "-"}
+
+	var tbl Table
+	tbl.Init()
+	tbl.AddColumn("Rate", 10, CELLFLOAT, COLJUSTIFYRIGHT)
+	tbl.AddColumn("DE", 12, CELLFLOAT, COLJUSTIFYRIGHT)
+	tbl.AddColumn("FR", 16, CELLDECIMAL, COLJUSTIFYRIGHT)
+	tbl.AddColumn("Count", 10, CELLINT, COLJUSTIFYRIGHT)
+	tbl.SetColDecimals(0, 4)
+	tbl.SetColNumberFormat(1, de)
+	tbl.SetColNumberFormat(2, fr)
+	tbl.SetColNumberFormat(3, cr)
+	if err := tbl.SetColNumberFormat(0, &NumberFormat{Negative: 7}); err == nil {
+		t.Errorf("numberformat_test: expected an error for an invalid negative style\n")
+	}
+	if err := tbl.SetColDecimals(0, -1); err == nil {
+		t.Errorf("numberformat_test: expected an error for negative decimals\n")
+	}
+
+	tbl.AddRow()
+	tbl.Putf(-1, 0, 1.56255)
+	tbl.Putf(-1, 1, -1234.5)
+	tbl.Putm(-1, 2, NewDecimal(-123456789, 2))
+	tbl.Puti(-1, 3, -12345)
+	tbl.AddRow()
+	tbl.Putf(-1, 0, 0)
+	tbl.Putf(-1, 1, 1234567)
+	tbl.Putm(-1, 2, NewDecimal(50, 2))
+	tbl.Puti(-1, 3, 0)
+
+	rows := [][]string{
+		{"1.5626", "-1.234,50", "(1\u202f234\u202f567,89)", "12,345 CR"},
+		{"0.0000", "1.234.567,00", "0,50", "-"},
+	}
+	for r := 0; r < len(rows); r++ {
+		for c := 0; c < len(rows[r]); c++ {
+			if s := tbl.cellText(c, &tbl.Row[r].Col[c]); s != rows[r][c] {
+				t.Errorf("numberformat_test: row %d col %d: expected %q, found %q\n", r, c, rows[r][c], s)
+			}
+		}
+	}
+
+	// every exporter uses the column formats
+	s, _ := tbl.SprintTable()
+	if !strings.Contains(s, "    1.5626     -1.234,50    (1\u202f234\u202f567,89)   12,345 CR\n") {
+		t.Errorf("numberformat_test: unexpected text output:\n%s\n", s)
+	}
+	var b bytes.Buffer
+	tbl.CSVprintTable(&b)
+	if !strings.Contains(b.String(), "\"   -1.234,50\",\"  (1\u202f234\u202f567,89)\",\" 12,345 CR\"") {
+		t.Errorf("numberformat_test: unexpected CSV output:\n%s\n", b.String())
+	}
+	b.Reset()
+	tbl.HTMLprintTable(&b)
+	if !strings.Contains(b.String(), "1.234.567,00") || !strings.Contains(b.String(), "12,345 CR") {
+		t.Errorf("numberformat_test: unexpected HTML output:\n%s\n", b.String())
+	}
+
+	if code := xlsxNumberFmt(cr, 0, ""); code != `#,##0;#,##0" CR";"-"` {
+		t.Errorf("numberformat_test: xlsxNumberFmt: found %s\n", code)
+	}
+	if code := xlsxNumberFmt(fr, 2, "€"); code != `"€"#,##0.00;("€"#,##0.00)` {
+		t.Errorf("numberformat_test: xlsxNumberFmt: found %s\n", code)
+	}
+}
@@ -2,19 +2,18 @@ package gotable
 
 import (
 	"bytes"
+	"context"
+	"fmt"
 	"io"
-	"io/ioutil"
 	"os"
 	"os/exec"
-	"path"
 	"strings"
-	"time"
 )
 
 // WKHTMLTOPDFCMD command : html > pdf
 const (
 	WKHTMLTOPDFCMD = "wkhtmltopdf"
-	TEMPSTORE      = "."
+	TEMPSTORE      = "."                   // no longer used, see WKHTMLTOPDFRenderer.TempDir
 	DATETIMEFMT    = "_2 Jan 2006 3:04 PM" // Actual Format: _2 Jan 2006 3:04 PM UTC
 )
 
@@ -29,89 +28,149 @@ type PDFProperty struct {
 	Option, Value string // Value could be optional
 }
 
-func (pt *PDFTable) writeTableOutput(w io.Writer, pdfProps []*PDFProperty) error {
+// PDFRenderer is implemented by the pdf backends. RenderPDF writes the tables
+// in m to w as a single pdf document laid out as described by opts, which may
+// be nil. It should stop and return an error when ctx is done.
+type PDFRenderer interface {
+	RenderPDF(ctx context.Context, w io.Writer, m []Table, opts *PDFOptions) error
+}
+
+// WKHTMLTOPDFRenderer renders the html output of the tables to pdf using the
+// wkhtmltopdf program. The options are passed to it as command line options,
+// followed by the raw options in PDFOptions.Extra. PDFOptions.Author is not
+// supported by wkhtmltopdf. The program is stopped if the context passed to
+// RenderPDF is done.
+type WKHTMLTOPDFRenderer struct {
+	Command string // the program to run, default WKHTMLTOPDFCMD
+	TempDir string // directory for the temporary html file, default os.TempDir()
+	Stdin   bool   // pipe the html to the program instead of using a temporary file
+}
+
+// defaultPDFRenderer is used by the tables that have no renderer of their own
+var defaultPDFRenderer PDFRenderer = &WKHTMLTOPDFRenderer{}
+
+// SetPDFRenderer sets the pdf backend used by the tables that have no backend
+// of their own. The default is WKHTMLTOPDFRenderer. Passing nil restores the
+// default.
+func SetPDFRenderer(r PDFRenderer) {
+	if r == nil {
+		r = &WKHTMLTOPDFRenderer{}
+	}
+	defaultPDFRenderer = r
+}
+
+// SetPDFRenderer sets the pdf backend used for this table, for example
+// &NativePDFRenderer{}. Passing nil makes the table use the package default,
+// see the SetPDFRenderer function.
+func (t *Table) SetPDFRenderer(r PDFRenderer) {
+	t.pdfRenderer = r
+}
+
+// getPDFRenderer returns the pdf backend of the table
+func (t *Table) getPDFRenderer() PDFRenderer {
+	if t.pdfRenderer != nil {
+		return t.pdfRenderer
+	}
+	return defaultPDFRenderer
+}
+
+func (pt *PDFTable) writeTableOutput(ctx context.Context, w io.Writer, opts *PDFOptions) error {
+	return pt.Table.getPDFRenderer().RenderPDF(ctx, w, []Table{*pt.Table}, opts)
+}
+
+// RenderPDF writes the tables in m to w as pdf using wkhtmltopdf
+func (r *WKHTMLTOPDFRenderer) RenderPDF(ctx context.Context, w io.Writer, m []Table, opts *PDFOptions) error {
+	funcname := "WKHTMLTOPDFRenderer.RenderPDF"
+
+	if opts == nil {
+		opts = &PDFOptions{}
+	}
 
 	// get html output first
 	var temp bytes.Buffer
 
 	// copy table object so that we can override properties over table
 	// so it won't affect original table
-	var pdfTable = *pt.Table
-	var ht = &HTMLTable{Table: &pdfTable}
-
-	// set custom values over ht
-	ht.Table.SetCSSFontUnit("px")
+	var pdfTables []Table
 
-	var tout TableExportType = ht
+	for _, tbl := range m {
+		var nTbl Table
+		nTbl = tbl
+		nTbl.SetCSSFontUnit("px")
+		pdfTables = append(pdfTables, nTbl)
+	}
 
-	if err := tout.writeTableOutput(&temp); err != nil {
-		errorLog("Unable to write html output of table to buffer: ", err.Error())
+	if len(pdfTables) == 1 {
+		var tout TableExportType = &HTMLTable{Table: &pdfTables[0]}
+		if err := tout.writeTableOutput(&temp); err != nil {
+			errorLog(funcname, ": Unable to write html output of table to buffer: ", err.Error())
+			return err
+		}
+	} else if err := MultiTableHTMLPrint(pdfTables, &temp); err != nil {
+		errorLog(funcname, ": Unable to write html output of table to buffer: ", err.Error())
 		return err
 	}
 	debugLog("HTML output for table has been generated and stored in temp buffer!")
 
-	htmlString := temp.String()
+	command := r.Command
+	if command == "" {
+		command = WKHTMLTOPDFCMD
+	}
 
-	timeCharReplacer := strings.NewReplacer(":", "-", ".", "", "T", "")
-	currentTime := timeCharReplacer.Replace(time.Now().Format(time.RFC3339Nano))
+	input := "-"
+	var stdin io.Reader = &temp
+	if !r.Stdin {
+		// create temp file, wkhtmltopdf only works with the html file extension
+		tempHTMLFile, err := os.CreateTemp(r.TempDir, "tablePDF_*.html")
+		if err != nil {
+			errorLog(funcname, ": Unable to create temporary html file for wkhtmltopdf: ", err.Error())
+			return err
+		}
 
-	// create temp file
-	filePath := path.Join(TEMPSTORE, "tablePDF_"+currentTime)
+		// remove this temp file after operation
+		defer os.Remove(tempHTMLFile.Name())
 
-	// only works with html file extension
-	// be careful, must append it
-	tempHTMLFile, err := os.Create(filePath + ".html")
-	if err != nil {
-		errorLog("Unable to create temporary html file for wkhtmltopdf stdin: ", err.Error())
-		return err
+		// write html string to file
+		_, err = tempHTMLFile.Write(temp.Bytes())
+		if cerr := tempHTMLFile.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			errorLog(funcname, ": Unable to write temporary html file: ", err.Error())
+			return err
+		}
+		debugLog("Temporary html file (input for wkhtmltopdf) absolute path: ", tempHTMLFile.Name())
+		input, stdin = tempHTMLFile.Name(), nil
 	}
-	// write html string to file
-	tempHTMLFile.WriteString(htmlString)
-	tempHTMLFile.Close()
-	debugLog("Temporary html file (stdin for wkhtmltopdf) absolute path: ", tempHTMLFile.Name())
-
-	// remove this temp file after operation
-	defer os.Remove(tempHTMLFile.Name())
 
-	// return output file path
-	if err = pt.writePDFBuffer(tempHTMLFile.Name(), pdfProps); err != nil {
-		errorLog("writePDFBuffer error : ", err.Error())
+	b, err := getPDFBuffer(ctx, command, input, stdin, opts.wkhtmltopdfProps())
+	if err != nil {
+		errorLog(funcname, ": getPDFBuffer error : ", err.Error())
 		return err
 	}
 
 	// write output to passed io.Writer interface object
-	w.Write(pt.buf.Bytes())
+	if _, err = w.Write(b); err != nil {
+		return err
+	}
 	infoLog("pdf output from buffer has been written to io.Writer typed object. :)")
-	return err
+	return nil
 }
 
-func getPDFBuffer(htmlInputFile string, pdfProps []*PDFProperty) ([]byte, error) {
-	// pdfOpts holds only options which does not require any value
-	pdfOpts := []string{}
-
-	// pdfOptsV holds options which has a value
-	pdfOptsV := []string{}
-
+// getPDFBuffer runs command to convert htmlInputFile to pdf and returns the
+// pdf. If htmlInputFile is "-" the html is read from stdin. The error returned
+// when the command fails includes what it wrote to stderr.
+func getPDFBuffer(ctx context.Context, command, htmlInputFile string, stdin io.Reader, pdfProps []*PDFProperty) ([]byte, error) {
+	// options are passed in the order of pdfProps
+	cmdArgs := []string{}
 	for _, prop := range pdfProps {
-		if prop.Option != "" && prop.Value != "" {
-			// option which has value
-			pdfOptsV = append(pdfOptsV, prop.Option)
-			pdfOptsV = append(pdfOptsV, prop.Value)
-		} else if prop.Option != "" && prop.Value == "" {
-			// option which does not require value
-			pdfOpts = append(pdfOpts, prop.Option)
+		if prop.Option == "" {
+			continue
+		}
+		cmdArgs = append(cmdArgs, prop.Option)
+		if prop.Value != "" {
+			cmdArgs = append(cmdArgs, prop.Value)
 		}
-	}
-
-	// make cmdArgs list from pdfOpts and pdfOptsV
-	cmdArgs := []string{}
-	// first append option which has no option
-	for _, opt := range pdfOpts {
-		cmdArgs = append(cmdArgs, opt)
-	}
-	// later append option with value
-	for _, optV := range pdfOptsV {
-		cmdArgs = append(cmdArgs, optV)
 	}
 
 	// append input and output finally
@@ -119,116 +178,50 @@ func getPDFBuffer(htmlInputFile string, pdfProps []*PDFProperty) ([]byte, error)
 	debugLog("Command line arguments for wkhtmltopdf:\n", cmdArgs, "\n\n")
 
 	// prepare command
-	wkhtmltopdf := exec.Command(WKHTMLTOPDFCMD, cmdArgs...)
-
-	// REF: https://github.com/aodin/go-pdf-server/blob/master/pdf_server.go
-
-	// get output pipeline
-	infoLog("wkhtmltopdf exec.Command > Getting Stdout pipe... ")
-	output, err := wkhtmltopdf.StdoutPipe()
-	if err != nil {
-		errorLog("wkhtmltopdf exec.command Stdout Pipe err: ", err.Error())
-		return nil, err
-	}
-
-	// Begin the command
-	infoLog("wkhtmltopdf exec.Command > Starting...")
-	if err = wkhtmltopdf.Start(); err != nil {
-		errorLog("wkhtmltopdf exec.command Start err: ", err.Error())
-		return nil, err
-	}
-
-	// Read the generated PDF from std out
-	infoLog("wkhtmltopdf exec.Command > Reading output...")
-	b, err := ioutil.ReadAll(output)
-	if err != nil {
-		errorLog("wkhtmltopdf ReadAll of output err: ", err.Error())
-		return nil, err
-	}
-
-	// End the command
-	infoLog("wkhtmltopdf exec.Command > Waiting for command to exit...")
-	if err = wkhtmltopdf.Wait(); err != nil {
-		errorLog("wkhtmltopdf exec.Command Wait err: ", err.Error())
+	wkhtmltopdf := exec.CommandContext(ctx, command, cmdArgs...)
+	wkhtmltopdf.Stdin = stdin
+	var stdout, stderr bytes.Buffer
+	wkhtmltopdf.Stdout = &stdout
+	wkhtmltopdf.Stderr = &stderr
+
+	infoLog("wkhtmltopdf exec.Command > Running...")
+	if err := wkhtmltopdf.Run(); err != nil {
+		if ctx.Err() != nil {
+			err = ctx.Err()
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			err = fmt.Errorf("%s: %s: %s", command, err.Error(), msg)
+		} else {
+			err = fmt.Errorf("%s: %s", command, err.Error())
+		}
+		errorLog("wkhtmltopdf exec.Command err: ", err.Error())
 		return nil, err
 	}
 	infoLog("wkhtmltopdf exec.Command > pdf has been rendered. :)")
 
+	b := stdout.Bytes()
 	return b, nil
 }
 
-func (pt *PDFTable) writePDFBuffer(htmlInputFile string, pdfProps []*PDFProperty) error {
-
-	b, err := getPDFBuffer(htmlInputFile, pdfProps)
-	if err != nil {
-		errorLog("getPDFBuffer Error : %s", err.Error())
-		return err
-	}
-
-	// write output to buffer
-	pt.buf.Write(b)
-	debugLog("Length of buffer for pdf output: ", pt.buf.Len(), " bytes")
-	debugLog("wkhtmltopdf pdf output has been written to internal buffer")
-
-	return nil
-}
-
-// MultiTablePDFPrint writes pdf output from each table to w io.Writer
+// MultiTablePDFPrint writes pdf output from each table to w io.Writer. The
+// backend of the first table is used for the whole document.
 func MultiTablePDFPrint(m []Table, w io.Writer, pdfProps []*PDFProperty) error {
-	funcname := "MultiTablePDFPrint"
-
-	// get html output first
-	var temp bytes.Buffer
-
-	// copy table object so that we can override properties over table
-	// so it won't affect original table
-	var pdfTables []Table
-
-	for _, tbl := range m {
-		var nTbl Table
-		nTbl = tbl
-		nTbl.SetCSSFontUnit("px")
-		pdfTables = append(pdfTables, nTbl)
-	}
-
-	if err := MultiTableHTMLPrint(pdfTables, &temp); err != nil {
-		errorLog("%s: Unable to write html output of table to buffer: ", funcname, err.Error())
-		return err
-	}
-	debugLog("HTML output for table has been generated and stored in temp buffer!")
-
-	htmlString := temp.String()
-
-	timeCharReplacer := strings.NewReplacer(":", "-", ".", "", "T", "")
-	currentTime := timeCharReplacer.Replace(time.Now().Format(time.RFC3339Nano))
-
-	// create temp file
-	filePath := path.Join(TEMPSTORE, "tablePDF_"+currentTime)
-
-	// only works with html file extension
-	// be careful, must append it
-	tempHTMLFile, err := os.Create(filePath + ".html")
-	if err != nil {
-		errorLog("%s: Unable to create temporary html file for wkhtmltopdf stdin: ", funcname, err.Error())
-		return err
-	}
-	// write html string to file
-	tempHTMLFile.WriteString(htmlString)
-	tempHTMLFile.Close()
-	debugLog("Temporary html file (stdin for wkhtmltopdf) absolute path: ", tempHTMLFile.Name())
+	return MultiTablePDFPrintContext(context.Background(), m, w, pdfProps)
+}
 
-	// remove this temp file after operation
-	defer os.Remove(tempHTMLFile.Name())
+// MultiTablePDFPrintContext is like MultiTablePDFPrint. The rendering is
+// stopped when ctx is done.
+func MultiTablePDFPrintContext(ctx context.Context, m []Table, w io.Writer, pdfProps []*PDFProperty) error {
+	return MultiTablePDFPrintOptions(ctx, m, w, &PDFOptions{Extra: pdfProps})
+}
 
-	// return output file path
-	b, err := getPDFBuffer(tempHTMLFile.Name(), pdfProps)
-	if err != nil {
-		errorLog("%s: getPDFBuffer error : ", funcname, err.Error())
-		return err
+// MultiTablePDFPrintOptions writes pdf output from each table to w io.Writer
+// using the page settings in opts. The backend of the first table is used for
+// the whole document.
+func MultiTablePDFPrintOptions(ctx context.Context, m []Table, w io.Writer, opts *PDFOptions) error {
+	r := defaultPDFRenderer
+	if len(m) > 0 {
+		r = m[0].getPDFRenderer()
 	}
-
-	// write output to passed io.Writer interface object
-	w.Write(b)
-	infoLog("pdf output from buffer has been written to io.Writer typed object. :)")
-	return err
+	return r.RenderPDF(ctx, w, m, opts)
 }
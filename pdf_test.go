@@ -0,0 +1,96 @@
+package gotable
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeWKHTMLTOPDF writes a shell script that stands in for wkhtmltopdf and
+// returns its path
+func fakeWKHTMLTOPDF(t *testing.T, dir, name, body string) string {
+	p := filepath.Join(dir, name)
+	if err := os.WriteFile(p, []byte("#!/bin/sh\n"+body+"\n"), 0755); err != nil {
+		t.Fatalf("pdf_test: %s\n", err.Error())
+	}
+	return p
+}
+
+func TestWKHTMLTOPDFRenderer(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("pdf_test: needs /bin/sh")
+	}
+	dir := t.TempDir()
+	tmp := t.TempDir()
+
+	// the fake converter copies its input, the next to last argument, to stdout
+	echo := fakeWKHTMLTOPDF(t, dir, "echo", `eval in=\${$(($#-1))}; if [ "$in" = "-" ]; then cat; else cat "$in"; fi`)
+	fail := fakeWKHTMLTOPDF(t, dir, "fail", `echo "unknown option --bogus" >&2; exit 1`)
+	slow := fakeWKHTMLTOPDF(t, dir, "slow", `exec sleep 5`)
+
+	var tbl Table
+	tbl.Init()
+	tbl.SetTitle("Rent Roll")
+	tbl.AddColumn("Unit", 6, CELLSTRING, COLJUSTIFYLEFT)
+	tbl.AddRow()
+	tbl.Puts(-1, 0, "101")
+
+	for _, stdin := range []bool{false, true} {
+		tbl.SetPDFRenderer(&WKHTMLTOPDFRenderer{Command: echo, TempDir: tmp, Stdin: stdin})
+		var b bytes.Buffer
+		if err := tbl.PDFprintTable(&b, []*PDFProperty{{Option: "--quiet"}}); err != nil {
+			t.Errorf("pdf_test: stdin=%v: %s\n", stdin, err.Error())
+			continue
+		}
+		if !strings.Contains(b.String(), "Rent Roll") {
+			t.Errorf("pdf_test: stdin=%v: expected the html of the table, found:\n%s\n", stdin, b.String())
+		}
+	}
+	if a, _ := os.ReadDir(tmp); len(a) != 0 {
+		t.Errorf("pdf_test: temporary files were not removed: %d left\n", len(a))
+	}
+
+	// typed options come first, then the raw options without a value and
+	// then the raw options with one
+	args := fakeWKHTMLTOPDF(t, dir, "args", `echo "$@"`)
+	tbl.SetPDFRenderer(&WKHTMLTOPDFRenderer{Command: args, TempDir: tmp, Stdin: true})
+	var b bytes.Buffer
+	opts := &PDFOptions{
+		PageSize: "A4", Orientation: PDFLANDSCAPE, MarginTop: 10, Grayscale: true, Footer: "Page {page} of {pages}",
+		Extra: []*PDFProperty{{Option: "--zoom", Value: "1.2"}, {Option: "--quiet"}, {Option: "--dpi", Value: "300"}, {Option: "--no-outline"}},
+	}
+	if err := tbl.PDFprintTableOptions(context.Background(), &b, opts); err != nil {
+		t.Errorf("pdf_test: PDFprintTableOptions: %s\n", err.Error())
+	}
+	expect := "--page-size A4 --orientation Landscape --margin-top 10mm --grayscale --footer-center Page [page] of [topage] --quiet --no-outline --zoom 1.2 --dpi 300 - -\n"
+	if b.String() != expect {
+		t.Errorf("pdf_test: expected arguments:\n%s\nfound:\n%s\n", expect, b.String())
+	}
+
+	tbl.SetPDFRenderer(&WKHTMLTOPDFRenderer{Command: fail, TempDir: tmp})
+	b.Reset()
+	if err := tbl.PDFprintTable(&b, nil); err == nil || !strings.Contains(err.Error(), "unknown option --bogus") {
+		t.Errorf("pdf_test: expected the stderr of the command in the error, found %v\n", err)
+	}
+
+	tbl.SetPDFRenderer(&WKHTMLTOPDFRenderer{Command: slow, TempDir: tmp})
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := tbl.PDFprintTableContext(ctx, &b, nil); err == nil || !strings.Contains(err.Error(), context.DeadlineExceeded.Error()) {
+		t.Errorf("pdf_test: expected a timeout, found %v\n", err)
+	}
+	if time.Since(start) > 3*time.Second {
+		t.Errorf("pdf_test: the command was not stopped by the context\n")
+	}
+
+	tbl.SetPDFRenderer(&WKHTMLTOPDFRenderer{Command: echo, TempDir: filepath.Join(tmp, "missing")})
+	if err := tbl.PDFprintTable(&b, nil); err == nil {
+		t.Errorf("pdf_test: expected an error for a missing temporary directory\n")
+	}
+}
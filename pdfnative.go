@@ -0,0 +1,472 @@
+package gotable
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// NativePDFRenderer lays out the tables and writes the pdf itself, so no
+// external program is needed. It uses the standard Helvetica fonts, repeats
+// the column headers at the top of each page and numbers the pages. Each table
+// starts on a new page. The default footer is "Page {page} of {pages}" and the
+// default margins are 12.7mm. PDFOptions.DPI and PDFOptions.Extra are ignored.
+// PDFOptions.Grayscale prints the colors of format rules as shades of gray.
+type NativePDFRenderer struct {
+	FontSize float64 // font size of the table in points, default 9
+}
+
+// pdf fonts
+const (
+	pdfFontRegular    = 1 // /F1, Helvetica
+	pdfFontBold       = 2 // /F2, Helvetica-Bold
+	pdfFontItalic     = 3 // /F3, Helvetica-Oblique
+	pdfFontBoldItalic = 4 // /F4, Helvetica-BoldOblique
+)
+
+// pdfFontNames are the base fonts of /F1 through /F4
+var pdfFontNames = []string{"Helvetica", "Helvetica-Bold", "Helvetica-Oblique", "Helvetica-BoldOblique"}
+
+// pdfHelveticaWidths are the widths of the characters 32 through 126 of
+// Helvetica in units of 1/1000 of the font size
+var pdfHelveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278, // space - /
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556, // 0 - ?
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778, // @ - O
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556, // P - _
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556, // ` - o
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584, // p - ~
+}
+
+// pdfHelveticaBoldWidths are the widths of the characters 32 through 126 of
+// Helvetica-Bold in units of 1/1000 of the font size
+var pdfHelveticaBoldWidths = [95]int{
+	278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278, // space - /
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611, // 0 - ?
+	975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778, // @ - O
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556, // P - _
+	333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611, // ` - o
+	611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584, // p - ~
+}
+
+// pdfWinAnsi maps the runes of the windows-1252 code page that are not in
+// latin-1 to their byte values
+var pdfWinAnsi = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87, 'ˆ': 0x88,
+	'‰': 0x89, 'Š': 0x8a, '‹': 0x8b, 'Œ': 0x8c, 'Ž': 0x8e, '‘': 0x91, '’': 0x92, '“': 0x93,
+	'”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '˜': 0x98, '™': 0x99, 'š': 0x9a, '›': 0x9b,
+	'œ': 0x9c, 'ž': 0x9e, 'Ÿ': 0x9f,
+}
+
+// pdfEncode returns s in the WinAnsi encoding used by the pdf fonts. Runes
+// that cannot be encoded are replaced by '?'.
+func pdfEncode(s string) []byte {
+	var b []byte
+	for _, r := range s {
+		switch {
+		case r < ' ':
+			b = append(b, ' ')
+		case r < 0x7f || (r >= 0xa0 && r <= 0xff):
+			b = append(b, byte(r))
+		case pdfWinAnsi[r] != 0:
+			b = append(b, pdfWinAnsi[r])
+		default:
+			b = append(b, '?')
+		}
+	}
+	return b
+}
+
+// pdfTextWidth returns the width in points of s printed in font at size
+func pdfTextWidth(s string, font int, size float64) float64 {
+	widths := &pdfHelveticaWidths
+	if font == pdfFontBold || font == pdfFontBoldItalic {
+		widths = &pdfHelveticaBoldWidths
+	}
+	w := 0
+	b := pdfEncode(s)
+	for i := 0; i < len(b); i++ {
+		switch {
+		case b[i] >= 32 && b[i] <= 126:
+			w += widths[b[i]-32]
+		case b[i] == 0x97: // em dash
+			w += 1000
+		default:
+			w += 556
+		}
+	}
+	return float64(w) * size / 1000
+}
+
+// pdfString returns s as a pdf string literal
+func pdfString(s string) string {
+	var buf bytes.Buffer
+	buf.WriteByte('(')
+	b := pdfEncode(s)
+	for i := 0; i < len(b); i++ {
+		switch {
+		case b[i] == '(' || b[i] == ')' || b[i] == '\\':
+			buf.WriteByte('\\')
+			buf.WriteByte(b[i])
+		case b[i] >= 0x80:
+			fmt.Fprintf(&buf, "\\%03o", b[i])
+		default:
+			buf.WriteByte(b[i])
+		}
+	}
+	buf.WriteByte(')')
+	return buf.String()
+}
+
+// pdfFit returns s shortened so that it is no wider than width
+func pdfFit(s string, font int, size, width float64) string {
+	r := []rune(s)
+	for len(r) > 0 && pdfTextWidth(string(r), font, size) > width {
+		r = r[:len(r)-1]
+	}
+	return string(r)
+}
+
+// pdfWrap splits s into lines no wider than width, breaking at spaces where it
+// can. Explicit line breaks in s are kept.
+func pdfWrap(s string, font int, size, width float64) []string {
+	var lines []string
+	paras := strings.Split(strings.Replace(s, "\r\n", "\n", -1), "\n")
+	for i := 0; i < len(paras); i++ {
+		line := ""
+		words := strings.Fields(paras[i])
+		for k := 0; k < len(words); k++ {
+			w := words[k]
+			if line != "" && pdfTextWidth(line+" "+w, font, size) <= width {
+				line += " " + w
+				continue
+			}
+			if line != "" {
+				lines = append(lines, line)
+			}
+			// split words that do not fit on a line by themselves
+			for pdfTextWidth(w, font, size) > width {
+				f := pdfFit(w, font, size, width)
+				if f == "" {
+					f = string([]rune(w)[:1])
+				}
+				lines = append(lines, f)
+				w = w[len(f):]
+			}
+			line = w
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// pdfLayout holds the state of the renderer while the pages are laid out
+type pdfLayout struct {
+	pageWidth, pageHeight    float64
+	top, right, bottom, left float64 // margins in points
+	fontSize                 float64
+	header, footer           string          // page header and footer text
+	pages                    []*bytes.Buffer // the content stream of each page
+	page                     *bytes.Buffer   // the current page
+	y                        float64         // top of the next line on the current page
+	gray                     bool            // print colors as shades of gray
+}
+
+// minY returns the lowest y position available for the table, leaving room
+// for the page footer
+func (l *pdfLayout) minY() float64 {
+	return l.bottom + 2*l.fontSize
+}
+
+func (l *pdfLayout) newPage() {
+	l.page = &bytes.Buffer{}
+	l.pages = append(l.pages, l.page)
+	l.y = l.pageHeight - l.top
+	if l.header != "" {
+		l.y -= 2 * l.fontSize
+	}
+}
+
+// text draws s with its baseline at x,y
+func (l *pdfLayout) text(s string, font int, size, x, y float64) {
+	if s == "" {
+		return
+	}
+	fmt.Fprintf(l.page, "BT /F%d %.2f Tf %.2f %.2f Td %s Tj ET\n", font, size, x, y, pdfString(s))
+}
+
+// rule draws a horizontal line at y from x1 to x2
+func (l *pdfLayout) rule(x1, x2, y float64) {
+	fmt.Fprintf(l.page, "0.5 w %.2f %.2f m %.2f %.2f l S\n", x1, y, x2, y)
+}
+
+// leading returns the line height for text of the supplied size
+func (l *pdfLayout) leading(size float64) float64 {
+	return size * 1.25
+}
+
+// paragraph draws each line of s across the width of the page
+func (l *pdfLayout) paragraph(s string, font int, size float64) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return
+	}
+	lines := pdfWrap(s, font, size, l.pageWidth-l.left-l.right)
+	for i := 0; i < len(lines); i++ {
+		if l.y-l.leading(size) < l.minY() {
+			l.newPage()
+		}
+		l.text(lines[i], font, size, l.left, l.y-size)
+		l.y -= l.leading(size)
+	}
+	l.y -= size / 2
+}
+
+// pdfColumns returns the left edge and width of each column of t, in points.
+// The widths follow ColDefs[i].Width and are scaled down to fit the page.
+func (l *pdfLayout) pdfColumns(t *Table) ([]float64, []float64) {
+	pad := l.fontSize / 2
+	charWidth := pdfTextWidth("0", pdfFontRegular, l.fontSize)
+	widths := make([]float64, len(t.ColDefs))
+	total := 0.0
+	for i := 0; i < len(t.ColDefs); i++ {
+		widths[i] = float64(t.ColDefs[i].Width)*charWidth + 2*pad
+		total += widths[i]
+	}
+	if avail := l.pageWidth - l.left - l.right; total > avail {
+		for i := 0; i < len(widths); i++ {
+			widths[i] *= avail / total
+		}
+	}
+	x := make([]float64, len(widths))
+	left := l.left
+	for i := 0; i < len(widths); i++ {
+		x[i] = left
+		left += widths[i]
+	}
+	return x, widths
+}
+
+// cell draws the lines of a cell in the column at x with width w
+func (l *pdfLayout) cell(lines []string, font int, x, w float64, right, underline bool) {
+	pad := l.fontSize / 2
+	for i := 0; i < len(lines); i++ {
+		s := pdfFit(lines[i], font, l.fontSize, w-2*pad)
+		tw := pdfTextWidth(s, font, l.fontSize)
+		tx := x + pad
+		if right {
+			tx = x + w - pad - tw
+		}
+		y := l.y - l.fontSize - float64(i)*l.leading(l.fontSize)
+		l.text(s, font, l.fontSize, tx, y)
+		if underline && s != "" {
+			fmt.Fprintf(l.page, "%.2f %.2f %.2f %.2f re f\n", tx, y-l.fontSize/8, tw, l.fontSize/18)
+		}
+	}
+}
+
+// styledCell draws a cell of a row that is height points high with st, the
+// style of the format rules that match it, which may be nil
+func (l *pdfLayout) styledCell(lines []string, st *Style, x, w, height float64, right bool) {
+	if st == nil {
+		l.cell(lines, pdfFontRegular, x, w, right, false)
+		return
+	}
+	font := pdfFontRegular
+	switch {
+	case st.Bold && st.Italic:
+		font = pdfFontBoldItalic
+	case st.Bold:
+		font = pdfFontBold
+	case st.Italic:
+		font = pdfFontItalic
+	}
+	l.page.WriteString("q\n")
+	if st.Background != "" {
+		fmt.Fprintf(l.page, "%s %.2f %.2f %.2f %.2f re f\n", pdfColor(st.Background, l.gray), x, l.y-height, w, height)
+	}
+	if st.Color != "" {
+		l.page.WriteString(pdfColor(st.Color, l.gray) + "\n")
+	} else {
+		l.page.WriteString("0 g\n")
+	}
+	l.cell(lines, font, x, w, right, st.Underline)
+	l.page.WriteString("Q\n")
+}
+
+// addTable lays out table t starting on a new page
+func (l *pdfLayout) addTable(t *Table) {
+	l.newPage()
+	l.paragraph(t.Title, pdfFontBold, l.fontSize*14/9)
+	l.paragraph(t.Section1, pdfFontBold, l.fontSize*11/9)
+	l.paragraph(t.Section2, pdfFontRegular, l.fontSize*10/9)
+	l.paragraph(t.Section3, pdfFontRegular, l.fontSize*10/9)
+
+	if err := t.HasHeaders(); err != nil {
+		l.paragraph(err.Error(), pdfFontRegular, l.fontSize)
+		return
+	}
+	if err := t.HasData(); err != nil {
+		l.paragraph(err.Error(), pdfFontRegular, l.fontSize)
+		return
+	}
+
+	t.AdjustAllColumnHeaders()
+	x, widths := l.pdfColumns(t)
+	right := x[len(x)-1] + widths[len(widths)-1]
+	lead := l.leading(l.fontSize)
+
+	header := func() {
+		for j := 0; j < len(t.ColDefs[0].Hdr); j++ {
+			for i := 0; i < len(t.ColDefs); i++ {
+				l.cell([]string{strings.TrimSpace(t.ColDefs[i].Hdr[j])}, pdfFontBold, x[i], widths[i], t.ColDefs[i].Justify == COLJUSTIFYRIGHT, false)
+			}
+			l.y -= lead
+		}
+		l.rule(l.left, right, l.y-lead/4)
+		l.y -= lead / 2
+	}
+	header()
+
+	for row := 0; row < len(t.Row); row++ {
+		cells := make([][]string, len(t.Row[row].Col))
+		height := 1
+		for col := 0; col < len(cells); col++ {
+			c := &t.Row[row].Col[col]
+			if c.Type == CELLSTRING {
+				cells[col] = pdfWrap(c.Sval, pdfFontRegular, l.fontSize, widths[col]-l.fontSize)
+			} else {
+				cells[col] = []string{t.cellText(col, c)}
+			}
+			if len(cells[col]) > height {
+				height = len(cells[col])
+			}
+		}
+
+		// a row is never split across pages
+		if l.y-float64(height)*lead < l.minY() {
+			l.newPage()
+			header()
+		}
+		if t.hasLineBefore(row) {
+			l.rule(l.left, right, l.y)
+			l.y -= lead / 4
+		}
+		for col := 0; col < len(cells); col++ {
+			l.styledCell(cells[col], t.ruleStyle(row, col), x[col], widths[col], float64(height)*lead, t.ColDefs[col].Justify == COLJUSTIFYRIGHT)
+		}
+		l.y -= float64(height) * lead
+		if t.hasLineAfter(row) {
+			l.rule(l.left, right, l.y-lead/4)
+			l.y -= lead / 2
+		}
+	}
+}
+
+// RenderPDF writes the tables in m to w as pdf
+func (r *NativePDFRenderer) RenderPDF(ctx context.Context, w io.Writer, m []Table, opts *PDFOptions) error {
+	if opts == nil {
+		opts = &PDFOptions{}
+	}
+	width, height, err := opts.pageSize()
+	if err != nil {
+		return fmt.Errorf("NativePDFRenderer: %s", err.Error())
+	}
+	l := pdfLayout{pageWidth: width, pageHeight: height, fontSize: r.FontSize, header: opts.Header, footer: opts.Footer, gray: opts.Grayscale}
+	if l.fontSize <= 0 {
+		l.fontSize = 9
+	}
+	if l.footer == "" {
+		l.footer = "Page {page} of {pages}"
+	}
+	margins := []*float64{&l.top, &l.right, &l.bottom, &l.left}
+	mm := []float64{opts.MarginTop, opts.MarginRight, opts.MarginBottom, opts.MarginLeft}
+	for i := 0; i < len(margins); i++ {
+		*margins[i] = 36
+		if mm[i] > 0 {
+			*margins[i] = pdfPoints(mm[i])
+		}
+	}
+
+	for i := 0; i < len(m); i++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		m[i].Recompute()
+		l.addTable(&m[i])
+	}
+	if len(l.pages) == 0 {
+		l.newPage()
+	}
+
+	// page headers and footers
+	size := l.fontSize * 8 / 9
+	for i := 0; i < len(l.pages); i++ {
+		l.page = l.pages[i]
+		if l.header != "" {
+			s := pageText(l.header, i+1, len(l.pages))
+			l.text(s, pdfFontRegular, size, (l.pageWidth-pdfTextWidth(s, pdfFontRegular, size))/2, l.pageHeight-l.top-size)
+		}
+		s := pageText(l.footer, i+1, len(l.pages))
+		l.text(s, pdfFontRegular, size, (l.pageWidth-pdfTextWidth(s, pdfFontRegular, size))/2, l.bottom)
+	}
+
+	title := opts.Title
+	if title == "" && len(m) > 0 {
+		title = strings.TrimSpace(m[0].Title)
+	}
+	return writePDFDocument(w, l.pageWidth, l.pageHeight, l.pages, title, opts.Author)
+}
+
+// writePDFDocument writes a pdf file with one page per content stream. The
+// content streams use /F1 through /F4 for the fonts in pdfFontNames.
+func writePDFDocument(w io.Writer, width, height float64, pages []*bytes.Buffer, title, author string) error {
+	var b bytes.Buffer
+	var offsets []int
+	obj := func(body string) {
+		offsets = append(offsets, b.Len())
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	b.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// the catalog, the page tree, the fonts and the info dictionary come
+	// first, then a page object and a content object per page
+	first := 4 + len(pdfFontNames)
+	kids := make([]string, len(pages))
+	for i := 0; i < len(pages); i++ {
+		kids[i] = fmt.Sprintf("%d 0 R", first+2*i)
+	}
+	obj("<< /Type /Catalog /Pages 2 0 R >>")
+	obj(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	fonts := ""
+	for i := 0; i < len(pdfFontNames); i++ {
+		obj("<< /Type /Font /Subtype /Type1 /BaseFont /" + pdfFontNames[i] + " /Encoding /WinAnsiEncoding >>")
+		fonts += fmt.Sprintf(" /F%d %d 0 R", i+1, i+3)
+	}
+	info := "<< /Producer (gotable)"
+	if title != "" {
+		info += " /Title " + pdfString(title)
+	}
+	if author != "" {
+		info += " /Author " + pdfString(author)
+	}
+	obj(info + " >>")
+	for i := 0; i < len(pages); i++ {
+		obj(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font <<%s >> >> /Contents %d 0 R >>",
+			width, height, fonts, first+1+2*i))
+		obj(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", pages[i].Len(), pages[i].String()))
+	}
+
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for i := 0; i < len(offsets); i++ {
+		fmt.Fprintf(&b, "%010d 00000 n \n", offsets[i])
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, first-1, xref)
+
+	_, err := w.Write(b.Bytes())
+	return err
+}
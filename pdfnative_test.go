@@ -0,0 +1,134 @@
+package gotable
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// stubPDFRenderer records the tables it was asked to render
+type stubPDFRenderer struct {
+	titles []string
+}
+
+func (r *stubPDFRenderer) RenderPDF(ctx context.Context, w io.Writer, m []Table, opts *PDFOptions) error {
+	for i := 0; i < len(m); i++ {
+		r.titles = append(r.titles, m[i].Title)
+	}
+	_, err := io.WriteString(w, "%PDF-stub")
+	return err
+}
+
+// checkPDFXref verifies that each entry of the xref table of pdf b points at
+// the start of its object
+func checkPDFXref(t *testing.T, b []byte) {
+	s := string(b)
+	i := strings.LastIndex(s, "startxref\n")
+	if i < 0 {
+		t.Errorf("pdfnative_test: no startxref\n")
+		return
+	}
+	xref, _ := strconv.Atoi(strings.Fields(s[i+len("startxref\n"):])[0])
+	if !strings.HasPrefix(s[xref:], "xref\n") {
+		t.Errorf("pdfnative_test: startxref does not point at the xref table\n")
+		return
+	}
+	lines := strings.Split(s[xref:], "\n")
+	var n int
+	fmt.Sscanf(lines[1], "0 %d", &n)
+	for obj := 1; obj < n; obj++ {
+		off, _ := strconv.Atoi(lines[2+obj][:10])
+		if !strings.HasPrefix(s[off:], fmt.Sprintf("%d 0 obj\n", obj)) {
+			t.Errorf("pdfnative_test: xref entry of object %d is wrong\n", obj)
+		}
+	}
+}
+
+func TestNativePDF(t *testing.T) {
+	var tbl Table
+	tbl.Init()
+	tbl.SetTitle("Rent (Roll)")
+	tbl.SetSection1("Building A")
+	tbl.AddColumn("Unit", 6, CELLSTRING, COLJUSTIFYLEFT)
+	tbl.AddColumn("Notes", 20, CELLSTRING, COLJUSTIFYLEFT)
+	tbl.AddColumn("Rent", 10, CELLFLOAT, COLJUSTIFYRIGHT)
+	for i := 0; i < 60; i++ {
+		tbl.AddRow()
+		tbl.Puts(-1, 0, strconv.Itoa(100+i))
+		tbl.Puts(-1, 1, "a note that is long enough to wrap onto a second line")
+		tbl.Putf(-1, 2, 1000+float64(i))
+	}
+	tbl.AddLineAfter(59)
+	tbl.SetPDFRenderer(&NativePDFRenderer{})
+
+	var b bytes.Buffer
+	if err := tbl.PDFprintTable(&b, nil); err != nil {
+		t.Errorf("pdfnative_test: PDFprintTable: %s\n", err.Error())
+		return
+	}
+	s := b.String()
+	pages := strings.Count(s, "/Type /Page ")
+	if !strings.HasPrefix(s, "%PDF-1.4") || pages < 2 {
+		t.Errorf("pdfnative_test: expected a pdf with several pages, found %d\n", pages)
+	}
+	if n := strings.Count(s, "(Unit) Tj"); n != pages {
+		t.Errorf("pdfnative_test: expected the headers on each of %d pages, found %d\n", pages, n)
+	}
+	for _, want := range []string{fmt.Sprintf("(Page 1 of %d) Tj", pages), fmt.Sprintf("(Page %d of %d) Tj", pages, pages), `(Rent \(Roll\)) Tj`, "(1,059.00) Tj"} {
+		if !strings.Contains(s, want) {
+			t.Errorf("pdfnative_test: output does not contain %s\n", want)
+		}
+	}
+	checkPDFXref(t, b.Bytes())
+
+	if w := pdfTextWidth("Hi", pdfFontRegular, 10); w != 9.44 {
+		t.Errorf("pdfnative_test: pdfTextWidth: expected 9.44, found %g\n", w)
+	}
+	if s := pdfString("é€"); s != `(\351\200)` {
+		t.Errorf("pdfnative_test: pdfString: found %s\n", s)
+	}
+	if a := pdfWrap("one two three", pdfFontRegular, 10, 40); len(a) != 2 || a[0] != "one two" {
+		t.Errorf("pdfnative_test: pdfWrap: found %q\n", a)
+	}
+
+	// page options
+	b.Reset()
+	opts := &PDFOptions{PageSize: "A4", Orientation: PDFLANDSCAPE, MarginLeft: 20, Header: "Confidential {page}/{pages}", Footer: "{page}", Author: "Finance"}
+	if err := tbl.PDFprintTableOptions(context.Background(), &b, opts); err != nil {
+		t.Errorf("pdfnative_test: PDFprintTableOptions: %s\n", err.Error())
+	}
+	s = b.String()
+	pages = strings.Count(s, "/Type /Page ")
+	for _, want := range []string{"/MediaBox [0 0 841.89 595.28]", fmt.Sprintf("(Confidential 1/%d) Tj", pages), "/Author (Finance)", "56.69 ", fmt.Sprintf("(%d) Tj", pages)} {
+		if !strings.Contains(s, want) {
+			t.Errorf("pdfnative_test: output with options does not contain %s\n", want)
+		}
+	}
+	if strings.Contains(s, "(Page 1 of") {
+		t.Errorf("pdfnative_test: the footer option should replace the default footer\n")
+	}
+	if err := tbl.PDFprintTableOptions(context.Background(), &b, &PDFOptions{PageSize: "B7"}); err == nil {
+		t.Errorf("pdfnative_test: expected an error for an unknown page size\n")
+	}
+
+	// the package default is used by tables without a renderer
+	stub := &stubPDFRenderer{}
+	SetPDFRenderer(stub)
+	defer SetPDFRenderer(nil)
+	var t2 Table
+	t2.Init()
+	t2.SetTitle("Second")
+	b.Reset()
+	if err := MultiTablePDFPrint([]Table{t2, t2}, &b, nil); err != nil || b.String() != "%PDF-stub" || len(stub.titles) != 2 {
+		t.Errorf("pdfnative_test: expected the stub renderer to render 2 tables, found %v\n", stub.titles)
+	}
+	b.Reset()
+	tbl.PDFprintTable(&b, nil)
+	if len(stub.titles) != 2 {
+		t.Errorf("pdfnative_test: the table's own renderer should have been used\n")
+	}
+}
@@ -0,0 +1,124 @@
+package gotable
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// pdf page orientations
+const (
+	PDFPORTRAIT  = 0 // the default
+	PDFLANDSCAPE = 1
+)
+
+// PDFOptions are the page settings for pdf output. Each PDFRenderer translates
+// them into its own settings. Zero values select the backend's defaults.
+type PDFOptions struct {
+	PageSize     string         // "Letter" (default), "Legal", "Tabloid", "A3", "A4" or "A5"
+	Orientation  int            // PDFPORTRAIT or PDFLANDSCAPE
+	MarginTop    float64        // margins in millimeters
+	MarginRight  float64        //
+	MarginBottom float64        //
+	MarginLeft   float64        //
+	DPI          int            // resolution used by html based backends
+	Grayscale    bool           // print without colors
+	Header       string         // text at the top of each page, {page} and {pages} are replaced by the page number and count
+	Footer       string         // text at the bottom of each page, with the same placeholders as Header
+	Title        string         // document title, default is the title of the first table
+	Author       string         // document author
+	Extra        []*PDFProperty // backend specific options. For wkhtmltopdf these are command line options, see wkhtmltopdfProps
+}
+
+// pdfPageSizes are the supported page sizes in millimeters, portrait
+var pdfPageSizes = map[string][2]float64{
+	"letter":  {215.9, 279.4},
+	"legal":   {215.9, 355.6},
+	"tabloid": {279.4, 431.8},
+	"a3":      {297, 420},
+	"a4":      {210, 297},
+	"a5":      {148, 210},
+}
+
+// pdfPoints returns mm in points
+func pdfPoints(mm float64) float64 {
+	return mm * 72 / 25.4
+}
+
+// pageSize returns the width and height of the page in points, or an error
+// if the page size is not known
+func (o *PDFOptions) pageSize() (float64, float64, error) {
+	name := o.PageSize
+	if name == "" {
+		name = "Letter"
+	}
+	sz, ok := pdfPageSizes[strings.ToLower(name)]
+	if !ok {
+		return 0, 0, fmt.Errorf("unknown page size %q", o.PageSize)
+	}
+	w, h := pdfPoints(sz[0]), pdfPoints(sz[1])
+	if o.Orientation == PDFLANDSCAPE {
+		w, h = h, w
+	}
+	return w, h, nil
+}
+
+// wkhtmltopdfProps returns the wkhtmltopdf command line options for o. Extra
+// follows the options made from the other fields. As in earlier versions, the
+// options in Extra that have no value come before those that have one; the
+// order within each group is kept.
+func (o *PDFOptions) wkhtmltopdfProps() []*PDFProperty {
+	var props []*PDFProperty
+	add := func(option, value string) {
+		props = append(props, &PDFProperty{Option: option, Value: value})
+	}
+	mm := func(v float64) string {
+		return strconv.FormatFloat(v, 'f', -1, 64) + "mm"
+	}
+	placeholders := strings.NewReplacer("{page}", "[page]", "{pages}", "[topage]")
+
+	if o.PageSize != "" {
+		add("--page-size", o.PageSize)
+	}
+	if o.Orientation == PDFLANDSCAPE {
+		add("--orientation", "Landscape")
+	}
+	if o.MarginTop > 0 {
+		add("--margin-top", mm(o.MarginTop))
+	}
+	if o.MarginRight > 0 {
+		add("--margin-right", mm(o.MarginRight))
+	}
+	if o.MarginBottom > 0 {
+		add("--margin-bottom", mm(o.MarginBottom))
+	}
+	if o.MarginLeft > 0 {
+		add("--margin-left", mm(o.MarginLeft))
+	}
+	if o.DPI > 0 {
+		add("--dpi", strconv.Itoa(o.DPI))
+	}
+	if o.Grayscale {
+		add("--grayscale", "")
+	}
+	if o.Title != "" {
+		add("--title", o.Title)
+	}
+	if o.Header != "" {
+		add("--header-center", placeholders.Replace(o.Header))
+	}
+	if o.Footer != "" {
+		add("--footer-center", placeholders.Replace(o.Footer))
+	}
+	for i := 0; i < len(o.Extra); i++ {
+		if o.Extra[i].Value == "" {
+			props = append(props, o.Extra[i])
+		}
+	}
+	for i := 0; i < len(o.Extra); i++ {
+		if o.Extra[i].Value != "" {
+			props = append(props, o.Extra[i])
+		}
+	}
+	return props
+}
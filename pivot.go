@@ -0,0 +1,156 @@
+package gotable
+
+import (
+	"sort"
+)
+
+// distinctCells returns the different non-empty values found in column col, sorted
+func (t *Table) distinctCells(col int) []Cell {
+	var a []Cell
+	for i := 0; i < len(t.Row); i++ {
+		c := t.Row[i].Col[col]
+		if isNullCell(&c) {
+			continue
+		}
+		j := sort.Search(len(a), func(k int) bool { return compareCells(&a[k], &c) >= 0 })
+		if j < len(a) && compareCells(&a[j], &c) == 0 {
+			continue
+		}
+		a = append(a, Cell{})
+		copy(a[j+1:], a[j:])
+		a[j] = c
+	}
+	return a
+}
+
+// findCell returns the index of c in a, which must be sorted, or -1 if it is not there
+func findCell(a []Cell, c *Cell) int {
+	j := sort.Search(len(a), func(k int) bool { return compareCells(&a[k], c) >= 0 })
+	if j < len(a) && compareCells(&a[j], c) == 0 {
+		return j
+	}
+	return -1
+}
+
+// Pivot creates a crosstab of the table. Each distinct value of column rowKeyCol
+// becomes a row, each distinct value of column colKeyCol becomes a column, and
+// each cell holds agg applied to the valueCol cells of the rows that have those
+// two keys. A Total column and a Total row hold agg applied to all the cells of
+// that row or column. Rows whose key cells are empty are ignored. Keys are sorted
+// ascending. The new table gets the date formats of this one.
+func (t *Table) Pivot(rowKeyCol, colKeyCol, valueCol int, agg AggFunc) (*Table, error) {
+	for _, col := range []int{rowKeyCol, colKeyCol, valueCol} {
+		if err := t.HasValidColumn(col); err != nil {
+			return nil, err
+		}
+	}
+
+	rowKeys := t.distinctCells(rowKeyCol)
+	colKeys := t.distinctCells(colKeyCol)
+
+	// gather the value cells for each crosstab cell, each row, each column and the grand total
+	cells := make([][][]Cell, len(rowKeys))
+	for i := 0; i < len(cells); i++ {
+		cells[i] = make([][]Cell, len(colKeys))
+	}
+	rowCells := make([][]Cell, len(rowKeys))
+	colCells := make([][]Cell, len(colKeys))
+	var allCells []Cell
+	for i := 0; i < len(t.Row); i++ {
+		r := findCell(rowKeys, &t.Row[i].Col[rowKeyCol])
+		c := findCell(colKeys, &t.Row[i].Col[colKeyCol])
+		if r < 0 || c < 0 {
+			continue
+		}
+		v := t.Row[i].Col[valueCol]
+		cells[r][c] = append(cells[r][c], v)
+		rowCells[r] = append(rowCells[r], v)
+		colCells[c] = append(colCells[c], v)
+		allCells = append(allCells, v)
+	}
+
+	// compute the crosstab. The last column and the last row hold the totals.
+	vals := make([][]Cell, len(rowKeys)+1)
+	for r := 0; r <= len(rowKeys); r++ {
+		vals[r] = make([]Cell, len(colKeys)+1)
+		for c := 0; c <= len(colKeys); c++ {
+			switch {
+			case r < len(rowKeys) && c < len(colKeys):
+				vals[r][c] = agg(cells[r][c])
+			case r < len(rowKeys):
+				vals[r][c] = agg(rowCells[r])
+			case c < len(colKeys):
+				vals[r][c] = agg(colCells[c])
+			default:
+				vals[r][c] = agg(allCells)
+			}
+		}
+	}
+
+	var p Table
+	p.Init()
+	p.DateFmt = t.DateFmt
+	p.DateTimeFmt = t.DateTimeFmt
+	p.Location = t.Location
+
+	// the row key column
+	rcd := t.ColDefs[rowKeyCol]
+	width := 0
+	for r := 0; r < len(rowKeys); r++ {
+		if l := displayWidth(t.cellText(rowKeyCol, &rowKeys[r])); l > width {
+			width = l
+		}
+	}
+	if rcd.CellType == CELLSTRING && width < displayWidth("Total") {
+		width = displayWidth("Total")
+	}
+	p.AddColumn(rcd.ColTitle, width, rcd.CellType, rcd.Justify)
+	pcd := &p.ColDefs[0]
+	pcd.Fdecimals, pcd.NumFmt, pcd.Currency = rcd.Fdecimals, rcd.NumFmt, rcd.Currency
+	pcd.DateFmt, pcd.Location = rcd.DateFmt, rcd.Location
+
+	// a column for each column key, and the totals. The column type is the type
+	// of the values that agg produced for it, and the formats are those of the
+	// value column.
+	vcd := t.ColDefs[valueCol]
+	for c := 0; c <= len(colKeys); c++ {
+		title := "Total"
+		if c < len(colKeys) {
+			title = t.cellText(colKeyCol, &colKeys[c])
+		}
+		celltype := 0
+		for r := 0; r <= len(rowKeys) && celltype == 0; r++ {
+			celltype = vals[r][c].Type
+		}
+		if celltype == 0 {
+			celltype = CELLFLOAT
+		}
+		p.AddColumn(title, 1, celltype, COLJUSTIFYRIGHT)
+		col := len(p.ColDefs) - 1
+		cd := &p.ColDefs[col]
+		cd.Fdecimals, cd.NumFmt, cd.Currency, cd.Rounding = vcd.Fdecimals, vcd.NumFmt, vcd.Currency, vcd.Rounding
+		cd.DateFmt, cd.Location = vcd.DateFmt, vcd.Location
+		for r := 0; r <= len(rowKeys); r++ {
+			if l := displayWidth(p.cellText(col, &vals[r][c])); l > cd.Width {
+				cd.Width = l
+			}
+		}
+		p.AdjustFormatString(cd)
+	}
+
+	for r := 0; r <= len(rowKeys); r++ {
+		p.AddRow()
+		if r < len(rowKeys) {
+			p.Put(-1, 0, rowKeys[r])
+		} else {
+			if rcd.CellType == CELLSTRING {
+				p.Puts(-1, 0, "Total")
+			}
+			p.AddLineBefore(r)
+		}
+		for c := 0; c <= len(colKeys); c++ {
+			p.Put(-1, c+1, vals[r][c])
+		}
+	}
+	return &p, nil
+}
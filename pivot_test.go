@@ -0,0 +1,126 @@
+package gotable
+
+import (
+	"testing"
+)
+
+func TestPivot(t *testing.T) {
+	var tbl Table
+	tbl.Init()
+	tbl.AddColumn("Property", 10, CELLSTRING, COLJUSTIFYLEFT)
+	tbl.AddColumn("Month", 5, CELLSTRING, COLJUSTIFYLEFT)
+	tbl.AddColumn("Revenue", 10, CELLFLOAT, COLJUSTIFYRIGHT)
+
+	var data = []struct {
+		prop, month string
+		rev         float64
+	}{
+		{"Oak", "Feb", 20}, {"Elm", "Jan", 100}, {"Oak", "Jan", 10}, {"Elm", "Jan", 5}, {"Pine", "Mar", 7},
+	}
+	for i := 0; i < len(data); i++ {
+		tbl.AddRow()
+		tbl.Puts(-1, 0, data[i].prop)
+		tbl.Puts(-1, 1, data[i].month)
+		tbl.Putf(-1, 2, data[i].rev)
+	}
+
+	if _, err := tbl.Pivot(0, 99, 2, AggSum); err == nil {
+		t.Errorf("pivot_test: expected an error for a bad column\n")
+	}
+	p, err := tbl.Pivot(0, 1, 2, AggSum)
+	if err != nil {
+		t.Fatalf("pivot_test: Pivot returned error: %s\n", err.Error())
+	}
+
+	titles := []string{"Property", "Feb", "Jan", "Mar", "Total"}
+	if p.ColCount() != len(titles) {
+		t.Fatalf("pivot_test: expected %d columns, found %d\n", len(titles), p.ColCount())
+	}
+	for i := 0; i < len(titles); i++ {
+		if p.ColDefs[i].ColTitle != titles[i] {
+			t.Errorf("pivot_test: column %d: expected title %s, found %s\n", i, titles[i], p.ColDefs[i].ColTitle)
+		}
+	}
+	if p.ColDefs[2].CellType != CELLFLOAT {
+		t.Errorf("pivot_test: expected CELLFLOAT column, found %d\n", p.ColDefs[2].CellType)
+	}
+
+	// Elm: - 105 - 105,  Oak: 20 10 - 30,  Pine: - - 7 7,  Total: 20 115 7 142
+	var expect = []struct {
+		key  string
+		vals []float64
+	}{
+		{"Elm", []float64{0, 105, 0, 105}},
+		{"Oak", []float64{20, 10, 0, 30}},
+		{"Pine", []float64{0, 0, 7, 7}},
+		{"Total", []float64{20, 115, 7, 142}},
+	}
+	if p.RowCount() != len(expect) {
+		t.Fatalf("pivot_test: expected %d rows, found %d\n", len(expect), p.RowCount())
+	}
+	for r := 0; r < len(expect); r++ {
+		if p.Gets(r, 0) != expect[r].key {
+			t.Errorf("pivot_test: row %d: expected key %s, found %s\n", r, expect[r].key, p.Gets(r, 0))
+		}
+		for c := 0; c < len(expect[r].vals); c++ {
+			if p.Getf(r, c+1) != expect[r].vals[c] {
+				t.Errorf("pivot_test: row %d col %d: expected %f, found %f\n", r, c+1, expect[r].vals[c], p.Getf(r, c+1))
+			}
+		}
+	}
+	if p.Type(0, 1) != 0 {
+		t.Errorf("pivot_test: expected an empty cell where there is no data, found type %d\n", p.Type(0, 1))
+	}
+	if !compareIntSlices(p.LineBefore, []int{3}) {
+		t.Errorf("pivot_test: expected a line before the total row, found %#v\n", p.LineBefore)
+	}
+}
+
+func TestPivotDisplayWidth(t *testing.T) {
+	var tbl Table
+	tbl.Init()
+	tbl.AddColumn("Property", 10, CELLSTRING, COLJUSTIFYLEFT)
+	tbl.AddColumn("Month", 5, CELLSTRING, COLJUSTIFYLEFT)
+	tbl.AddColumn("Revenue", 10, CELLFLOAT, COLJUSTIFYRIGHT)
+	for _, prop := range []string{"Café Olé", "東京"} {
+		tbl.AddRow()
+		tbl.Puts(-1, 0, prop)
+		tbl.Puts(-1, 1, "Jan")
+		tbl.Putf(-1, 2, 10)
+	}
+	p, err := tbl.Pivot(0, 1, 2, AggSum)
+	if err != nil {
+		t.Fatalf("pivot_test: Pivot returned error: %s\n", err.Error())
+	}
+	if p.ColDefs[0].Width != 8 {
+		t.Errorf("pivot_test: expected the row key column to be 8 columns wide, found %d\n", p.ColDefs[0].Width)
+	}
+}
+
+func TestPivotFormats(t *testing.T) {
+	var tbl Table
+	tbl.Init()
+	tbl.AddColumn("Property", 10, CELLSTRING, COLJUSTIFYLEFT)
+	tbl.AddColumn("Month", 5, CELLSTRING, COLJUSTIFYLEFT)
+	tbl.AddColumn("Rent", 10, CELLDECIMAL, COLJUSTIFYRIGHT)
+	tbl.AddColumn("Rate", 10, CELLFLOAT, COLJUSTIFYRIGHT)
+	tbl.SetColDecimal(2, 2, "$", ROUNDHALFUP)
+	tbl.SetColDecimals(3, 4)
+	tbl.AddRow()
+	tbl.Puts(-1, 0, "Oak")
+	tbl.Puts(-1, 1, "Jan")
+	tbl.Putm(-1, 2, NewDecimal(123456789, 2))
+	tbl.Putf(-1, 3, 0.12345)
+
+	p, err := tbl.Pivot(0, 1, 2, AggSum)
+	if err != nil {
+		t.Fatalf("pivot_test: Pivot returned error: %s\n", err.Error())
+	}
+	if s := p.cellText(1, &p.Row[0].Col[1]); s != "$1,234,567.89" || p.ColDefs[1].Width != len(s) || p.ColDefs[2].Currency != "$" {
+		t.Errorf("pivot_test: expected $1,234,567.89 in a column of width 13, found %q width %d\n", s, p.ColDefs[1].Width)
+	}
+	p, _ = tbl.Pivot(0, 1, 3, AggSum)
+	if s := p.cellText(1, &p.Row[0].Col[1]); s != "0.1235" {
+		t.Errorf("pivot_test: expected 0.1235, found %q\n", s)
+	}
+}
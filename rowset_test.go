@@ -142,3 +142,71 @@ func compareIntSlices(a, b []int) bool {
 	}
 	return true
 }
+
+func TestRowIdentity(t *testing.T) {
+	var tbl Table
+	tbl.Init()
+	tbl.AddColumn("Key", 5, CELLINT, COLJUSTIFYRIGHT)
+	tbl.AddColumn("Name", 10, CELLSTRING, COLJUSTIFYLEFT)
+	for i := 0; i < 6; i++ {
+		tbl.AddRow()
+		tbl.Puti(-1, 0, int64(10-i))
+		tbl.Puts(-1, 1, "row")
+	}
+
+	// decorate the rows with keys 10 and 7, which are at index 0 and 3
+	rsid := tbl.CreateRowset()
+	tbl.AppendToRowset(rsid, 0)
+	tbl.AppendToRowset(rsid, 3)
+	tbl.AddLineAfter(0)
+	tbl.AddLineBefore(3)
+	tbl.SetCellCSS(3, 1, []*CSSProperty{{Name: "color", Value: "red"}})
+	id7 := tbl.RowID(3)
+
+	// after sorting, the row with key 7 is at index 2 and key 10 at index 5
+	tbl.Sort(0, tbl.RowCount()-1, 0)
+	if tbl.FindRow(id7) != 2 {
+		t.Errorf("rowset_test: Expected row id %d at index 2, found %d\n", id7, tbl.FindRow(id7))
+	}
+	if rs := tbl.GetRowset(rsid); !compareIntSlices(rs, []int{5, 2}) {
+		t.Errorf("rowset_test: Expected rowset %#v, found %#v\n", []int{5, 2}, rs)
+	}
+	if !compareIntSlices(tbl.LineAfter, []int{5}) || !compareIntSlices(tbl.LineBefore, []int{2}) {
+		t.Errorf("rowset_test: line markers did not follow their rows: after %#v, before %#v\n", tbl.LineAfter, tbl.LineBefore)
+	}
+	if _, ok := tbl.CSS[tbl.getCSSMapKeyForCell(2, 1)]; !ok {
+		t.Errorf("rowset_test: cell css did not follow its row\n")
+	}
+
+	// deleting row 0 shifts everything up by one
+	tbl.DeleteRow(0)
+	if rs := tbl.GetRowset(rsid); !compareIntSlices(rs, []int{4, 1}) {
+		t.Errorf("rowset_test: Expected rowset %#v, found %#v\n", []int{4, 1}, rs)
+	}
+	if !compareIntSlices(tbl.LineAfter, []int{4}) || !compareIntSlices(tbl.LineBefore, []int{1}) {
+		t.Errorf("rowset_test: line markers not adjusted after delete: after %#v, before %#v\n", tbl.LineAfter, tbl.LineBefore)
+	}
+
+	// deleting a decorated row removes its decorations
+	tbl.DeleteRow(1)
+	if rs := tbl.GetRowset(rsid); !compareIntSlices(rs, []int{3}) {
+		t.Errorf("rowset_test: Expected rowset %#v, found %#v\n", []int{3}, rs)
+	}
+	if len(tbl.LineBefore) != 0 {
+		t.Errorf("rowset_test: Expected no LineBefore, found %#v\n", tbl.LineBefore)
+	}
+	for k := range tbl.CSS {
+		if strings.HasPrefix(k, "row:") {
+			t.Errorf("rowset_test: Expected css of deleted row to be removed, found %s\n", k)
+		}
+	}
+
+	// move the last row to the top
+	tbl.MoveRow(3, 0)
+	if rs := tbl.GetRowset(rsid); !compareIntSlices(rs, []int{0}) {
+		t.Errorf("rowset_test: Expected rowset %#v, found %#v\n", []int{0}, rs)
+	}
+	if !compareIntSlices(tbl.LineAfter, []int{0}) {
+		t.Errorf("rowset_test: Expected LineAfter %#v, found %#v\n", []int{0}, tbl.LineAfter)
+	}
+}
@@ -0,0 +1,149 @@
+package gotable
+
+import (
+	"sort"
+	"strings"
+)
+
+// SortKey describes one column of a multi-key sort. Rows are ordered by the
+// first key; rows that compare equal on it are ordered by the next key, and
+// so on. Rows that compare equal on every key keep their original order.
+type SortKey struct {
+	Col        int                 // index of the column to sort on
+	Desc       bool                // true = descending, false = ascending
+	NullsFirst bool                // empty cells go first when true, last otherwise. Not affected by Desc
+	Cmp        func(a, b Cell) int // optional comparator, returns <0, 0, >0. Only called for non-empty cells
+}
+
+// isNullCell reports whether c holds no value: it was never set, or it was
+// set to null
+func isNullCell(c *Cell) bool {
+	return c.Type == 0 || c.Type == CELLNULL
+}
+
+// compareCells returns <0 if a sorts before b, 0 if they are equal, and >0 if
+// a sorts after b. Cells of different types are ordered by their type.
+// Strings are compared without regard to case.
+func compareCells(a, b *Cell) int {
+	if a.Type != b.Type {
+		return a.Type - b.Type
+	}
+	switch a.Type {
+	case CELLINT:
+		switch {
+		case a.Ival < b.Ival:
+			return -1
+		case a.Ival > b.Ival:
+			return 1
+		}
+	case CELLFLOAT:
+		switch {
+		case a.Fval < b.Fval:
+			return -1
+		case a.Fval > b.Fval:
+			return 1
+		}
+	case CELLDECIMAL:
+		return a.Mval.Cmp(b.Mval)
+	case CELLSTRING:
+		return strings.Compare(strings.ToLower(a.Sval), strings.ToLower(b.Sval))
+	case CELLDATE, CELLDATETIME:
+		switch {
+		case a.Dval.Before(b.Dval):
+			return -1
+		case a.Dval.After(b.Dval):
+			return 1
+		}
+	}
+	return 0
+}
+
+// compare returns the ordering of cells a and b according to key k
+func (k *SortKey) compare(a, b *Cell) int {
+	an, bn := isNullCell(a), isNullCell(b)
+	switch {
+	case an && bn:
+		return 0
+	case an:
+		if k.NullsFirst {
+			return -1
+		}
+		return 1
+	case bn:
+		if k.NullsFirst {
+			return 1
+		}
+		return -1
+	}
+
+	var r int
+	if k.Cmp != nil {
+		r = k.Cmp(*a, *b)
+	} else {
+		r = compareCells(a, b)
+	}
+	if k.Desc {
+		return -r
+	}
+	return r
+}
+
+// Sort sorts rows (from,to) by column col ascending. It is shorthand for
+// SortBy with a single ascending key.
+func (t *Table) Sort(from, to, col int) {
+	t.SortBy(from, to, SortKey{Col: col})
+}
+
+// SortBy sorts rows (from,to) using the supplied keys. The sort is stable:
+// rows that compare equal on every key keep their relative order. Keys that
+// refer to a column outside the table are ignored.
+func (t *Table) SortBy(from, to int, keys ...SortKey) {
+	if from < 0 {
+		from = 0
+	}
+	if to >= len(t.Row) {
+		to = len(t.Row) - 1
+	}
+	if from >= to {
+		return
+	}
+
+	var k []SortKey
+	for i := 0; i < len(keys); i++ {
+		if keys[i].Col >= 0 && keys[i].Col < len(t.ColDefs) {
+			k = append(k, keys[i])
+		}
+	}
+	if len(k) == 0 {
+		return
+	}
+
+	// sort a list of row indeces rather than the rows themselves, then
+	// put the rows in their new order in one pass
+	idx := make([]int, to-from+1)
+	for i := 0; i < len(idx); i++ {
+		idx[i] = from + i
+	}
+	sort.SliceStable(idx, func(i, j int) bool {
+		ri, rj := &t.Row[idx[i]], &t.Row[idx[j]]
+		for n := 0; n < len(k); n++ {
+			if r := k[n].compare(&ri.Col[k[n].Col], &rj.Col[k[n].Col]); r != 0 {
+				return r < 0
+			}
+		}
+		return false
+	})
+	t.reorderRows(from, idx)
+}
+
+// reorderRows rearranges the rows starting at index from so that the new row
+// from+i is the old row idx[i]. Line markers, rowsets and cell CSS stay with
+// their rows.
+func (t *Table) reorderRows(from int, idx []int) {
+	rows := make([]Colset, len(t.Row))
+	copy(rows, t.Row)
+	for i := 0; i < len(idx); i++ {
+		rows[from+i] = t.Row[idx[i]]
+	}
+	t.setRows(rows)
+}
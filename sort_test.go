@@ -0,0 +1,68 @@
+package gotable
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSortBy(t *testing.T) {
+	var tbl Table
+	tbl.Init()
+	tbl.AddColumn("Date", 10, CELLDATE, COLJUSTIFYLEFT)
+	tbl.AddColumn("Amount", 10, CELLFLOAT, COLJUSTIFYRIGHT)
+	tbl.AddColumn("Seq", 5, CELLINT, COLJUSTIFYRIGHT)
+
+	d1 := time.Date(2017, time.March, 1, 0, 0, 0, 0, time.UTC)
+	d2 := time.Date(2017, time.March, 2, 0, 0, 0, 0, time.UTC)
+	var data = []struct {
+		dt  time.Time
+		amt float64
+	}{
+		{d2, 10}, {d1, 5}, {d2, 30}, {d1, 5}, {d1, 20}, {d2, 10},
+	}
+	for i := 0; i < len(data); i++ {
+		tbl.AddRow()
+		tbl.Putd(-1, 0, data[i].dt)
+		tbl.Putf(-1, 1, data[i].amt)
+		tbl.Puti(-1, 2, int64(i))
+	}
+
+	// date asc, then amount desc. Ties keep their original order.
+	tbl.SortBy(0, tbl.RowCount()-1, SortKey{Col: 0}, SortKey{Col: 1, Desc: true})
+	seqExpect := []int64{4, 1, 3, 2, 0, 5}
+	for i := 0; i < len(seqExpect); i++ {
+		if tbl.Geti(i, 2) != seqExpect[i] {
+			t.Errorf("sort_test: row %d: expected seq %d, found %d\n", i, seqExpect[i], tbl.Geti(i, 2))
+		}
+	}
+
+	// empty cells go last by default, first when asked, regardless of direction
+	tbl.AddRow()
+	tbl.Puti(-1, 2, 99)
+	tbl.SortBy(0, tbl.RowCount()-1, SortKey{Col: 1, Desc: true})
+	if tbl.Geti(tbl.RowCount()-1, 2) != 99 {
+		t.Errorf("sort_test: expected empty cell last, found seq %d\n", tbl.Geti(tbl.RowCount()-1, 2))
+	}
+	tbl.SortBy(0, tbl.RowCount()-1, SortKey{Col: 1, NullsFirst: true})
+	if tbl.Geti(0, 2) != 99 {
+		t.Errorf("sort_test: expected empty cell first, found seq %d\n", tbl.Geti(0, 2))
+	}
+
+	// custom comparator: odd sequence numbers before even ones
+	tbl.SortBy(0, tbl.RowCount()-1, SortKey{Col: 2, Cmp: func(a, b Cell) int {
+		return int(b.Ival%2 - a.Ival%2)
+	}})
+	for i := 0; i < tbl.RowCount()-1; i++ {
+		if tbl.Geti(i, 2)%2 == 0 && tbl.Geti(i+1, 2)%2 == 1 {
+			t.Errorf("sort_test: custom comparator: even seq %d found before odd seq %d\n", tbl.Geti(i, 2), tbl.Geti(i+1, 2))
+		}
+	}
+
+	// a partial range leaves the rows outside of it alone
+	tbl.SortBy(2, 4, SortKey{Col: 2, Desc: true})
+	for i := 2; i < 4; i++ {
+		if tbl.Geti(i, 2) < tbl.Geti(i+1, 2) {
+			t.Errorf("sort_test: range sort failed: %d < %d\n", tbl.Geti(i, 2), tbl.Geti(i+1, 2))
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package gotable
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SQLOptions controls how FromSQLRows builds a table
+type SQLOptions struct {
+	Title   string         // the table title
+	MaxRows int            // read at most this many rows. 0 means no limit
+	Types   map[string]int // cell types to use for the named columns instead of the ones from the driver
+}
+
+// sqlDateFormats are the layouts tried when a driver returns a date as text
+var sqlDateFormats = []string{
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02T15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// sqlCellType returns the cell type for a column of a query result
+func sqlCellType(ct *sql.ColumnType) int {
+	dbtype := strings.ToUpper(ct.DatabaseTypeName())
+	switch {
+	case dbtype == "DATE":
+		return CELLDATE
+	case strings.Contains(dbtype, "DECIMAL"), strings.Contains(dbtype, "NUMERIC"), dbtype == "MONEY":
+		return CELLDECIMAL
+	}
+	st := ct.ScanType()
+	if st == nil {
+		return CELLSTRING
+	}
+	switch st {
+	case reflect.TypeOf(sql.NullInt64{}), reflect.TypeOf(sql.NullInt32{}), reflect.TypeOf(sql.NullInt16{}),
+		reflect.TypeOf(sql.NullByte{}):
+		return CELLINT
+	case reflect.TypeOf(sql.NullFloat64{}):
+		return CELLFLOAT
+	case reflect.TypeOf(sql.NullTime{}):
+		return CELLDATETIME
+	}
+	switch cellTypeOf(st) {
+	case CELLINT:
+		return CELLINT
+	case CELLFLOAT:
+		return CELLFLOAT
+	case CELLDATE:
+		return CELLDATETIME
+	}
+	return CELLSTRING
+}
+
+// FromSQLRows creates a table from the result of a query. Each column of the
+// result becomes a column of the table, titled with the column name. The cell
+// type of a column comes from the type information of the driver: integers
+// become CELLINT, floating point numbers CELLFLOAT, DECIMAL and NUMERIC columns
+// CELLDECIMAL with the column's scale (2 if the driver does not know it), DATE
+// columns CELLDATE, other times CELLDATETIME, and everything else CELLSTRING.
+// opts.Types overrides this.
+// NULL values become null cells. Columns are made wide enough for their title
+// and values. The caller still owns rows and must close it. If opts is nil the
+// default options are used.
+func FromSQLRows(rows *sql.Rows, opts *SQLOptions) (*Table, error) {
+	funcname := "FromSQLRows"
+	if opts == nil {
+		opts = &SQLOptions{}
+	}
+	cts, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", funcname, err.Error())
+	}
+
+	var t Table
+	t.Init()
+	t.Title = opts.Title
+	for i := 0; i < len(cts); i++ {
+		celltype, ok := opts.Types[cts[i].Name()]
+		if !ok {
+			celltype = sqlCellType(cts[i])
+		}
+		justify := COLJUSTIFYLEFT
+		if celltype == CELLINT || celltype == CELLFLOAT || celltype == CELLDECIMAL {
+			justify = COLJUSTIFYRIGHT
+		}
+		t.AddColumn(cts[i].Name(), 1, celltype, justify)
+		if celltype == CELLDECIMAL {
+			if _, scale, ok := cts[i].DecimalSize(); ok && scale >= 0 && scale <= 18 {
+				t.SetColDecimal(i, int(scale), "", ROUNDHALFUP)
+			}
+		}
+	}
+
+	vals := make([]interface{}, len(cts))
+	ptrs := make([]interface{}, len(cts))
+	for i := 0; i < len(vals); i++ {
+		ptrs[i] = &vals[i]
+	}
+	for (opts.MaxRows <= 0 || len(t.Row) < opts.MaxRows) && rows.Next() {
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, fmt.Errorf("%s: %s", funcname, err.Error())
+		}
+		t.AddRow()
+		for i := 0; i < len(vals); i++ {
+			if err := t.putSQLValue(i, vals[i]); err != nil {
+				return nil, fmt.Errorf("%s: row %d, column %q: %s", funcname, len(t.Row)-1, cts[i].Name(), err.Error())
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %s", funcname, err.Error())
+	}
+
+	for i := 0; i < len(t.ColDefs); i++ {
+		t.setColumnWidth(i, 0)
+	}
+	return &t, nil
+}
+
+// putSQLValue stores v, a value returned by a database driver, in column col of
+// the last row, converting it to the type of the column
+func (t *Table) putSQLValue(col int, v interface{}) error {
+	if v == nil {
+		t.PutNull(-1, col)
+		return nil
+	}
+	if b, ok := v.([]byte); ok {
+		v = string(b)
+	}
+	cd := &t.ColDefs[col]
+	var err error
+	switch cd.CellType {
+	case CELLINT:
+		switch x := v.(type) {
+		case int64:
+			t.Puti(-1, col, x)
+		case float64:
+			i, ok := roundInt(x)
+			if !ok {
+				err = fmt.Errorf("%g does not fit in an int64", x)
+				break
+			}
+			t.Puti(-1, col, i)
+		case bool:
+			if x {
+				t.Puti(-1, col, 1)
+			} else {
+				t.Puti(-1, col, 0)
+			}
+		case string:
+			var i int64
+			if i, err = strconv.ParseInt(strings.TrimSpace(x), 10, 64); err == nil {
+				t.Puti(-1, col, i)
+			}
+		default:
+			err = fmt.Errorf("cannot convert %T to an int", v)
+		}
+	case CELLFLOAT:
+		switch x := v.(type) {
+		case int64:
+			t.Putf(-1, col, float64(x))
+		case float64:
+			t.Putf(-1, col, x)
+		case string:
+			var f float64
+			if f, err = strconv.ParseFloat(strings.TrimSpace(x), 64); err == nil {
+				t.Putf(-1, col, f)
+			}
+		default:
+			err = fmt.Errorf("cannot convert %T to a float", v)
+		}
+	case CELLDECIMAL:
+		switch x := v.(type) {
+		case int64:
+			err = t.putm(len(t.Row)-1, col, Decimal{Units: x})
+		case float64:
+			err = t.putm(len(t.Row)-1, col, DecimalFromFloat(x, cd.Fdecimals, cd.Rounding))
+		case string:
+			var d Decimal
+			if d, err = ParseDecimal(x); err == nil {
+				err = t.putm(len(t.Row)-1, col, d)
+			}
+		default:
+			err = fmt.Errorf("cannot convert %T to a decimal", v)
+		}
+	case CELLDATE, CELLDATETIME:
+		switch x := v.(type) {
+		case time.Time:
+			t.putdint(-1, col, x, cd.CellType)
+		case string:
+			err = fmt.Errorf("cannot parse %q as a date", x)
+			for i := 0; i < len(sqlDateFormats); i++ {
+				if d, e := time.Parse(sqlDateFormats[i], strings.TrimSpace(x)); e == nil {
+					t.putdint(-1, col, d, cd.CellType)
+					err = nil
+					break
+				}
+			}
+		default:
+			err = fmt.Errorf("cannot convert %T to a date", v)
+		}
+	default:
+		switch x := v.(type) {
+		case string:
+			t.Puts(-1, col, x)
+		case time.Time:
+			t.Puts(-1, col, x.Format(t.DateTimeFmt))
+		default:
+			t.Puts(-1, col, fmt.Sprint(v))
+		}
+	}
+	return err
+}
@@ -0,0 +1,168 @@
+package gotable
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeDriver is a database/sql driver that returns the same canned result for
+// every query
+type fakeDriver struct{}
+type fakeConn struct{}
+type fakeStmt struct{}
+type fakeRows struct{ row int }
+
+type fakeColumn struct {
+	name     string
+	dbtype   string
+	scanType reflect.Type
+	scale    int64 // -1 = unknown
+}
+
+var fakeColumns = []fakeColumn{
+	{"id", "INTEGER", reflect.TypeOf(int64(0)), -1},
+	{"name", "VARCHAR", reflect.TypeOf(""), -1},
+	{"rate", "DOUBLE", reflect.TypeOf(float64(0)), -1},
+	{"balance", "DECIMAL", reflect.TypeOf([]byte(nil)), 2},
+	{"opened", "DATE", reflect.TypeOf(time.Time{}), -1},
+	{"updated", "TIMESTAMP", reflect.TypeOf(time.Time{}), -1},
+	{"code", "CHAR", reflect.TypeOf([]byte(nil)), -1},
+}
+
+var fakeData = [][]driver.Value{
+	{int64(1), "Alice", 1.25, []byte("1050.10"), time.Date(2019, 1, 2, 0, 0, 0, 0, time.UTC), time.Date(2020, 5, 6, 7, 8, 9, 0, time.UTC), []byte("A1")},
+	{int64(2), nil, nil, []byte("-20.05"), nil, "2020-05-07 10:11:12", nil},
+	{int64(3), "Carol", 3.5, nil, "2018-12-31", nil, []byte("C3")},
+}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) { return fakeConn{}, nil }
+
+func (fakeConn) Prepare(query string) (driver.Stmt, error) { return fakeStmt{}, nil }
+func (fakeConn) Close() error                              { return nil }
+func (fakeConn) Begin() (driver.Tx, error)                 { return nil, fmt.Errorf("not supported") }
+
+func (fakeStmt) Close() error  { return nil }
+func (fakeStmt) NumInput() int { return 0 }
+func (fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return nil, fmt.Errorf("not supported")
+}
+func (fakeStmt) Query(args []driver.Value) (driver.Rows, error) { return &fakeRows{}, nil }
+
+func (r *fakeRows) Columns() []string {
+	var a []string
+	for i := 0; i < len(fakeColumns); i++ {
+		a = append(a, fakeColumns[i].name)
+	}
+	return a
+}
+func (r *fakeRows) Close() error { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.row >= len(fakeData) {
+		return io.EOF
+	}
+	copy(dest, fakeData[r.row])
+	r.row++
+	return nil
+}
+func (r *fakeRows) ColumnTypeDatabaseTypeName(i int) string { return fakeColumns[i].dbtype }
+func (r *fakeRows) ColumnTypeScanType(i int) reflect.Type   { return fakeColumns[i].scanType }
+func (r *fakeRows) ColumnTypePrecisionScale(i int) (int64, int64, bool) {
+	return 10, fakeColumns[i].scale, fakeColumns[i].scale >= 0
+}
+
+func init() {
+	sql.Register("gotablefake", fakeDriver{})
+}
+
+func TestFromSQLRows(t *testing.T) {
+	db, err := sql.Open("gotablefake", "")
+	if err != nil {
+		t.Errorf("sqlrows_test: sql.Open: %s\n", err.Error())
+		return
+	}
+	defer db.Close()
+
+	rows, err := db.Query("SELECT * FROM accounts")
+	if err != nil {
+		t.Errorf("sqlrows_test: Query: %s\n", err.Error())
+		return
+	}
+	tbl, err := FromSQLRows(rows, &SQLOptions{Title: "Accounts", Types: map[string]int{"code": CELLSTRING}})
+	rows.Close()
+	if err != nil {
+		t.Errorf("sqlrows_test: FromSQLRows: %s\n", err.Error())
+		return
+	}
+
+	types := []int{CELLINT, CELLSTRING, CELLFLOAT, CELLDECIMAL, CELLDATE, CELLDATETIME, CELLSTRING}
+	for i := 0; i < len(types); i++ {
+		if tbl.ColDefs[i].CellType != types[i] || tbl.ColDefs[i].ColTitle != fakeColumns[i].name {
+			t.Errorf("sqlrows_test: column %d: expected %s type %d, found %s type %d\n", i, fakeColumns[i].name, types[i], tbl.ColDefs[i].ColTitle, tbl.ColDefs[i].CellType)
+		}
+	}
+	if tbl.RowCount() != 3 {
+		t.Errorf("sqlrows_test: expected 3 rows, found %d\n", tbl.RowCount())
+	}
+	if tbl.Geti(2, 0) != 3 || tbl.Gets(0, 1) != "Alice" || tbl.Getf(2, 2) != 3.5 || tbl.Gets(2, 6) != "C3" {
+		t.Errorf("sqlrows_test: unexpected values\n")
+	}
+	if s := tbl.Sum(3); s.Type != CELLDECIMAL || s.Mval.String() != "1030.05" {
+		t.Errorf("sqlrows_test: expected a balance total of 1030.05, found %#v\n", s)
+	}
+	if d := tbl.Getd(2, 4); !d.Equal(time.Date(2018, 12, 31, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("sqlrows_test: expected 2018-12-31, found %s\n", d)
+	}
+	if d := tbl.Getd(1, 5); !d.Equal(time.Date(2020, 5, 7, 10, 11, 12, 0, time.UTC)) {
+		t.Errorf("sqlrows_test: expected 2020-05-07 10:11:12, found %s\n", d)
+	}
+	for _, rc := range [][2]int{{1, 1}, {1, 2}, {2, 3}, {1, 4}, {2, 5}, {1, 6}} {
+		if c := tbl.Get(rc[0], rc[1]); c.Type != CELLNULL {
+			t.Errorf("sqlrows_test: expected a null at %d,%d, found %#v\n", rc[0], rc[1], c)
+		}
+	}
+	if tbl.ColDefs[1].Width != 5 || tbl.ColDefs[3].Width != 8 {
+		t.Errorf("sqlrows_test: expected widths 5 and 8, found %d and %d\n", tbl.ColDefs[1].Width, tbl.ColDefs[3].Width)
+	}
+	s, _ := tbl.SprintTable()
+	if !strings.Contains(s, "Alice  1.25  1,050.10  01/02/2019") {
+		t.Errorf("sqlrows_test: unexpected text output:\n%s\n", s)
+	}
+
+	// MaxRows
+	rows, _ = db.Query("SELECT * FROM accounts")
+	tbl, err = FromSQLRows(rows, &SQLOptions{MaxRows: 2})
+	rows.Close()
+	if err != nil || tbl.RowCount() != 2 {
+		t.Errorf("sqlrows_test: MaxRows: expected 2 rows, found %d (%v)\n", tbl.RowCount(), err)
+	}
+
+	// a value that cannot be converted
+	rows, _ = db.Query("SELECT * FROM accounts")
+	_, err = FromSQLRows(rows, &SQLOptions{Types: map[string]int{"name": CELLINT}})
+	rows.Close()
+	if err == nil {
+		t.Errorf("sqlrows_test: expected an error converting a name to an int\n")
+	}
+}
+
+func TestSQLValueRange(t *testing.T) {
+	var tbl Table
+	tbl.Init()
+	tbl.AddColumn("Count", 8, CELLINT, COLJUSTIFYRIGHT)
+	tbl.AddRow()
+	if err := tbl.putSQLValue(0, 2.6); err != nil || tbl.Geti(0, 0) != 3 {
+		t.Errorf("sqlrows_test: expected 3, found %d %v\n", tbl.Geti(0, 0), err)
+	}
+	for _, f := range []float64{1e19, -1e19, math.NaN()} {
+		if err := tbl.putSQLValue(0, f); err == nil {
+			t.Errorf("sqlrows_test: expected an error for %g\n", f)
+		}
+	}
+}
@@ -0,0 +1,396 @@
+package gotable
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StructOptions controls how FromStructs builds a table
+type StructOptions struct {
+	Title string // the table title
+}
+
+// structField describes the column made from one struct field
+type structField struct {
+	index    int    // index of the field in the struct
+	name     string // name of the field in the struct
+	title    string
+	width    int // 0 = wide enough for the title and the values
+	celltype int
+	justify  int
+	decimals int // -1 = not set
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+var decimalType = reflect.TypeOf(Decimal{})
+
+// cellTypeOf returns the cell type for values of Go type rt, or 0 if there is none
+func cellTypeOf(rt reflect.Type) int {
+	if rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+	switch rt {
+	case timeType:
+		return CELLDATE
+	case decimalType:
+		return CELLDECIMAL
+	}
+	switch rt.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return CELLINT
+	case reflect.Float32, reflect.Float64:
+		return CELLFLOAT
+	case reflect.String:
+		return CELLSTRING
+	}
+	return 0
+}
+
+// structFields returns the columns described by the exported fields of struct
+// type rt and their gotable tags. See FromStructs for the tag format.
+func structFields(rt reflect.Type) ([]structField, error) {
+	var fields []structField
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		tag := f.Tag.Get("gotable")
+		if f.PkgPath != "" || tag == "-" {
+			continue // unexported or skipped
+		}
+		sf := structField{index: i, name: f.Name, title: f.Name, celltype: cellTypeOf(f.Type), decimals: -1}
+		if sf.celltype == 0 {
+			if tag == "" {
+				continue // no column can hold it, such as a bool or a struct
+			}
+			return nil, fmt.Errorf("field %s: unsupported type %s", f.Name, f.Type)
+		}
+		if tag != "" {
+			for _, kv := range strings.Split(tag, ",") {
+				a := strings.SplitN(kv, "=", 2)
+				if len(a) != 2 {
+					return nil, fmt.Errorf("field %s: bad tag setting %q", f.Name, kv)
+				}
+				key, val := strings.TrimSpace(a[0]), strings.TrimSpace(a[1])
+				var err error
+				switch key {
+				case "title":
+					sf.title = val
+				case "width":
+					sf.width, err = strconv.Atoi(val)
+				case "decimals":
+					sf.decimals, err = strconv.Atoi(val)
+				case "type":
+					sf.celltype, err = structCellType(f, val)
+				case "justify":
+					switch val {
+					case "left":
+						sf.justify = COLJUSTIFYLEFT
+					case "right":
+						sf.justify = COLJUSTIFYRIGHT
+					default:
+						err = fmt.Errorf("unknown justify %q", val)
+					}
+				default:
+					err = fmt.Errorf("unknown tag setting %q", key)
+				}
+				if err != nil {
+					return nil, fmt.Errorf("field %s: %s", f.Name, err.Error())
+				}
+			}
+		}
+		if sf.justify == 0 {
+			sf.justify = COLJUSTIFYLEFT
+			if sf.celltype == CELLINT || sf.celltype == CELLFLOAT || sf.celltype == CELLDECIMAL {
+				sf.justify = COLJUSTIFYRIGHT
+			}
+		}
+		fields = append(fields, sf)
+	}
+	return fields, nil
+}
+
+// structCellType returns the cell type named by name, checking that the value
+// of field f can be stored in it
+func structCellType(f reflect.StructField, name string) (int, error) {
+	celltype := cellTypeByName(name)
+	if celltype == 0 || celltype == CELLNULL {
+		return 0, fmt.Errorf("unknown type %q", name)
+	}
+	isNumber := func(ct int) bool { return ct == CELLINT || ct == CELLFLOAT || ct == CELLDECIMAL }
+	isDate := func(ct int) bool { return ct == CELLDATE || ct == CELLDATETIME }
+	ft := cellTypeOf(f.Type)
+	if ft == celltype || (isNumber(ft) && isNumber(celltype)) || (isDate(ft) && isDate(celltype)) {
+		return celltype, nil
+	}
+	return 0, fmt.Errorf("type %s cannot hold a %s", name, f.Type)
+}
+
+// FromStructs creates a table from rows, which must be a slice of structs or
+// pointers to structs. Each exported field becomes a column, configured by its
+// gotable tag (see below), and each element becomes a row. Nil pointer fields
+// become null cells. Columns without a width are made wide enough for their
+// title and values. If opts is nil the default options are used.
+//
+// A tag looks like this:
+//
+//	`gotable:"title=Amount,width=12,type=float,justify=right,decimals=2"`
+//
+// All the settings are optional. The title defaults to the field name, type to
+// a type that suits the field, and justify to right for numbers and left for
+// everything else. type is one of int, float, decimal, string, date or
+// datetime. A field tagged `gotable:"-"` is skipped, and so is an untagged field
+// of a type that no column can hold, such as a bool or a struct. Tagging such a
+// field is an error.
+func FromStructs(rows interface{}, opts *StructOptions) (*Table, error) {
+	funcname := "FromStructs"
+	v := reflect.ValueOf(rows)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, fmt.Errorf("%s: rows must be a slice of structs, not %T", funcname, rows)
+	}
+	et := v.Type().Elem()
+	if et.Kind() == reflect.Ptr {
+		et = et.Elem()
+	}
+	if et.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%s: rows must be a slice of structs, not %T", funcname, rows)
+	}
+	fields, err := structFields(et)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", funcname, err.Error())
+	}
+	if opts == nil {
+		opts = &StructOptions{}
+	}
+
+	var t Table
+	t.Init()
+	t.Title = opts.Title
+	for i := 0; i < len(fields); i++ {
+		t.AddColumn(fields[i].title, 1, fields[i].celltype, fields[i].justify)
+		if fields[i].decimals >= 0 {
+			if fields[i].celltype == CELLDECIMAL {
+				t.SetColDecimal(i, fields[i].decimals, "", ROUNDHALFUP)
+			} else {
+				t.ColDefs[i].Fdecimals = fields[i].decimals
+			}
+		}
+	}
+
+	for r := 0; r < v.Len(); r++ {
+		t.AddRow()
+		sv := v.Index(r)
+		if sv.Kind() == reflect.Ptr {
+			if sv.IsNil() {
+				continue
+			}
+			sv = sv.Elem()
+		}
+		for i := 0; i < len(fields); i++ {
+			fv := sv.Field(fields[i].index)
+			if fv.Kind() == reflect.Ptr {
+				if fv.IsNil() {
+					t.PutNull(-1, i)
+					continue
+				}
+				fv = fv.Elem()
+			}
+			if err := t.putValue(i, fv); err != nil {
+				return nil, fmt.Errorf("%s: row %d, field %s: %s", funcname, r, fields[i].name, err.Error())
+			}
+		}
+	}
+
+	for i := 0; i < len(fields); i++ {
+		t.setColumnWidth(i, fields[i].width)
+	}
+	return &t, nil
+}
+
+// putValue stores the field value fv in column col of the last row. It returns
+// an error if a number does not fit in the column.
+func (t *Table) putValue(col int, fv reflect.Value) error {
+	var f float64
+	var isInt bool
+	var i int64
+	switch fv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, isInt = fv.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if fv.Uint() > math.MaxInt64 {
+			return fmt.Errorf("%d does not fit in an int64", fv.Uint())
+		}
+		i, isInt = int64(fv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		f = fv.Float()
+	case reflect.String:
+		t.Puts(-1, col, fv.String())
+		return nil
+	}
+	var c = Cell{Type: CELLFLOAT, Fval: f}
+	switch fv.Type() {
+	case timeType:
+		if t.ColDefs[col].CellType == CELLDATETIME {
+			t.Putdt(-1, col, fv.Interface().(time.Time))
+		} else {
+			t.Putd(-1, col, fv.Interface().(time.Time))
+		}
+		return nil
+	case decimalType:
+		c = Cell{Type: CELLDECIMAL, Mval: fv.Interface().(Decimal)}
+	default:
+		if isInt {
+			c = Cell{Type: CELLINT, Ival: i}
+		}
+	}
+	c = t.convertNumber(col, &c)
+	if c.Type == CELLDECIMAL {
+		return t.putm(len(t.Row)-1, col, c.Mval)
+	}
+	t.Put(-1, col, c)
+	return nil
+}
+
+// ToStructs copies the rows of the table into dst, which must be a pointer to a
+// slice of structs or of pointers to structs. The slice is replaced by one
+// element per row. Each field is set from the column whose title matches the
+// field's gotable tag title, or its name if it has none; fields with no column
+// are left alone. Null cells set pointer fields to nil and leave other fields
+// at their zero value. An error is returned if a cell cannot be stored in its
+// field.
+func (t *Table) ToStructs(dst interface{}) error {
+	funcname := "ToStructs"
+	pv := reflect.ValueOf(dst)
+	if pv.Kind() != reflect.Ptr || pv.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("%s: dst must be a pointer to a slice of structs, not %T", funcname, dst)
+	}
+	sv := pv.Elem()
+	et := sv.Type().Elem()
+	isPtr := et.Kind() == reflect.Ptr
+	if isPtr {
+		et = et.Elem()
+	}
+	if et.Kind() != reflect.Struct {
+		return fmt.Errorf("%s: dst must be a pointer to a slice of structs, not %T", funcname, dst)
+	}
+	fields, err := structFields(et)
+	if err != nil {
+		return fmt.Errorf("%s: %s", funcname, err.Error())
+	}
+	cols := make([]int, len(fields))
+	for i := 0; i < len(fields); i++ {
+		cols[i] = t.colIndex(fields[i].title)
+	}
+
+	out := reflect.MakeSlice(sv.Type(), len(t.Row), len(t.Row))
+	for r := 0; r < len(t.Row); r++ {
+		ev := out.Index(r)
+		if isPtr {
+			ev.Set(reflect.New(et))
+			ev = ev.Elem()
+		}
+		for i := 0; i < len(fields); i++ {
+			if cols[i] < 0 {
+				continue
+			}
+			c := &t.Row[r].Col[cols[i]]
+			if isNullCell(c) {
+				continue
+			}
+			fv := ev.Field(fields[i].index)
+			if fv.Kind() == reflect.Ptr {
+				fv.Set(reflect.New(fv.Type().Elem()))
+				fv = fv.Elem()
+			}
+			if err := t.setField(fv, c); err != nil {
+				return fmt.Errorf("%s: row %d, field %s: %s", funcname, r, fields[i].name, err.Error())
+			}
+		}
+	}
+	sv.Set(out)
+	return nil
+}
+
+// setField stores the value of c in fv. It returns an error if the value does
+// not fit in the type of fv, e.g. 300 in a uint8 or -1 in a uint.
+func (t *Table) setField(fv reflect.Value, c *Cell) error {
+	switch fv.Type() {
+	case timeType:
+		if c.Type != CELLDATE && c.Type != CELLDATETIME {
+			break
+		}
+		fv.Set(reflect.ValueOf(c.Dval))
+		return nil
+	case decimalType:
+		switch c.Type {
+		case CELLDECIMAL:
+			fv.Set(reflect.ValueOf(c.Mval))
+			return nil
+		case CELLINT:
+			fv.Set(reflect.ValueOf(Decimal{Units: c.Ival}))
+			return nil
+		}
+	}
+	v, isNumber := cellFloat(c)
+	switch fv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if !isNumber {
+			break
+		}
+		if i, ok := cellInt(c, v); ok && !fv.OverflowInt(i) {
+			fv.SetInt(i)
+			return nil
+		}
+		return fmt.Errorf("%s does not fit in a %s", t.cellString(c), fv.Type())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if !isNumber {
+			break
+		}
+		if i, ok := cellInt(c, v); ok && i >= 0 && !fv.OverflowUint(uint64(i)) {
+			fv.SetUint(uint64(i))
+			return nil
+		}
+		return fmt.Errorf("%s does not fit in a %s", t.cellString(c), fv.Type())
+	case reflect.Float32, reflect.Float64:
+		if !isNumber {
+			break
+		}
+		if math.IsInf(v, 0) || math.IsNaN(v) || !fv.OverflowFloat(v) {
+			fv.SetFloat(v)
+			return nil
+		}
+		return fmt.Errorf("%s does not fit in a %s", t.cellString(c), fv.Type())
+	case reflect.String:
+		if c.Type == CELLSTRING {
+			fv.SetString(c.Sval)
+			return nil
+		}
+	}
+	return fmt.Errorf("cannot store a cell of type %d in a %s", c.Type, fv.Type())
+}
+
+// cellInt returns the number in c, whose value as a float64 is v, rounded to
+// an int64. It returns false if the number is out of the range of an int64.
+func cellInt(c *Cell, v float64) (int64, bool) {
+	switch c.Type {
+	case CELLINT:
+		return c.Ival, true
+	case CELLDECIMAL:
+		d, err := c.Mval.rescale(0, ROUNDHALFUP)
+		return d.Units, err == nil
+	}
+	return roundInt(v)
+}
+
+// roundInt returns f rounded to an int64. It returns false if f is NaN or out
+// of the range of an int64.
+func roundInt(f float64) (int64, bool) {
+	f = math.Round(f)
+	if math.IsNaN(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+		return 0, false
+	}
+	return int64(f), true
+}
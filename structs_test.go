@@ -0,0 +1,181 @@
+package gotable
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+type rentRow struct {
+	Unit    string    `gotable:"title=Unit,width=6"`
+	Tenant  string    `gotable:"justify=left"`
+	Rent    Decimal   `gotable:"title=Monthly Rent,decimals=2"`
+	Sqft    int       `gotable:"title=Sq Ft"`
+	Rate    float64   `gotable:"type=float,decimals=3"`
+	Start   time.Time `gotable:"title=Lease Start,type=date"`
+	Deposit *float64  `gotable:"type=decimal"`
+	note    string
+	Skip    []int `gotable:"-"`
+}
+
+func TestFromStructs(t *testing.T) {
+	d := time.Date(2020, time.March, 1, 0, 0, 0, 0, time.UTC)
+	dep := 500.0
+	rows := []rentRow{
+		{Unit: "101", Tenant: "Smith", Rent: NewDecimal(125000, 2), Sqft: 800, Rate: 1.5625, Start: d, Deposit: &dep},
+		{Unit: "102", Tenant: "Jones-Whitaker", Rent: NewDecimal(98050, 2), Sqft: 650, Rate: 1.5085, Start: d.AddDate(0, 2, 0)},
+	}
+	tbl, err := FromStructs(rows, &StructOptions{Title: "Rent Roll"})
+	if err != nil {
+		t.Errorf("structs_test: FromStructs: %s\n", err.Error())
+		return
+	}
+	if tbl.ColCount() != 7 || tbl.RowCount() != 2 {
+		t.Errorf("structs_test: expected 7 columns and 2 rows, found %d and %d\n", tbl.ColCount(), tbl.RowCount())
+	}
+	var m = []struct {
+		title    string
+		celltype int
+		justify  int
+		width    int
+	}{
+		{"Unit", CELLSTRING, COLJUSTIFYLEFT, 6},
+		{"Tenant", CELLSTRING, COLJUSTIFYLEFT, 14},
+		{"Monthly Rent", CELLDECIMAL, COLJUSTIFYRIGHT, 12},
+		{"Sq Ft", CELLINT, COLJUSTIFYRIGHT, 5},
+		{"Rate", CELLFLOAT, COLJUSTIFYRIGHT, 5},
+		{"Lease Start", CELLDATE, COLJUSTIFYLEFT, 11},
+		{"Deposit", CELLDECIMAL, COLJUSTIFYRIGHT, 7},
+	}
+	for i := 0; i < len(m); i++ {
+		cd := tbl.ColDefs[i]
+		if cd.ColTitle != m[i].title || cd.CellType != m[i].celltype || cd.Justify != m[i].justify || cd.Width != m[i].width {
+			t.Errorf("structs_test: column %d: expected %v, found %q %d %d %d\n", i, m[i], cd.ColTitle, cd.CellType, cd.Justify, cd.Width)
+		}
+	}
+	if tbl.ColDefs[4].Fdecimals != 3 {
+		t.Errorf("structs_test: expected 3 decimals, found %d\n", tbl.ColDefs[4].Fdecimals)
+	}
+	if tbl.Getm(0, 6).String() != "500.00" || !tbl.IsNull(1, 6) {
+		t.Errorf("structs_test: Deposit: expected 500.00 and null, found %s and %#v\n", tbl.Getm(0, 6), tbl.Get(1, 6))
+	}
+	if s := tbl.Sum(2); s.Mval.String() != "2230.50" {
+		t.Errorf("structs_test: Sum of rent: expected 2230.50, found %s\n", s.Mval.String())
+	}
+	s, _ := tbl.SprintTable()
+	if !strings.Contains(s, "Jones-Whitaker        980.50") {
+		t.Errorf("structs_test: unexpected text output:\n%s\n", s)
+	}
+
+	// and back again
+	var back []*rentRow
+	if err := tbl.ToStructs(&back); err != nil {
+		t.Errorf("structs_test: ToStructs: %s\n", err.Error())
+		return
+	}
+	if len(back) != 2 || back[1].Tenant != "Jones-Whitaker" || back[0].Rent.Cmp(rows[0].Rent) != 0 ||
+		back[1].Sqft != 650 || back[0].Rate != 1.5625 || !back[1].Start.Equal(rows[1].Start) ||
+		back[0].Deposit == nil || *back[0].Deposit != 500 || back[1].Deposit != nil {
+		t.Errorf("structs_test: ToStructs: unexpected result %#v %#v\n", back[0], back[1])
+	}
+
+	// errors
+	if _, err := FromStructs(42, nil); err == nil {
+		t.Errorf("structs_test: expected an error for a non-slice\n")
+	}
+	type badTag struct {
+		A string `gotable:"type=int"`
+	}
+	if _, err := FromStructs([]badTag{}, nil); err == nil {
+		t.Errorf("structs_test: expected an error for a string field with type=int\n")
+	}
+	type badType struct {
+		A map[string]int `gotable:"title=A"`
+	}
+	if _, err := FromStructs([]badType{}, nil); err == nil {
+		t.Errorf("structs_test: expected an error for a tagged map field\n")
+	}
+
+	// untagged fields that no column can hold are skipped
+	type withOther struct {
+		Unit   string
+		Active bool
+		Meta   struct{ Owner string }
+		Extra  map[string]int
+	}
+	other := []withOther{{Unit: "101", Active: true}}
+	o, err := FromStructs(other, nil)
+	if err != nil || o.ColCount() != 1 || o.Gets(0, 0) != "101" {
+		t.Errorf("structs_test: expected only the Unit column, found %v\n", err)
+	} else if err := o.ToStructs(&other); err != nil || other[0].Unit != "101" || other[0].Active {
+		t.Errorf("structs_test: ToStructs: unexpected result %#v %v\n", other, err)
+	}
+	type mismatch struct {
+		Tenant int
+	}
+	var mm []mismatch
+	if err := tbl.ToStructs(&mm); err == nil {
+		t.Errorf("structs_test: expected an error storing a string in an int\n")
+	}
+}
+
+func TestToStructsOverflow(t *testing.T) {
+	var tbl Table
+	tbl.Init()
+	tbl.AddColumn("Count", 8, CELLINT, COLJUSTIFYRIGHT)
+	tbl.AddColumn("Rate", 8, CELLFLOAT, COLJUSTIFYRIGHT)
+	tbl.AddColumn("Rent", 8, CELLDECIMAL, COLJUSTIFYRIGHT)
+	tbl.AddRow()
+	tbl.Puti(-1, 0, 200)
+	tbl.Putf(-1, 1, 1e10)
+	tbl.Putm(-1, 2, NewDecimal(25050, 2))
+	tbl.AddRow()
+	tbl.Puti(-1, 0, 300)
+	tbl.Putf(-1, 1, -1)
+	tbl.Putm(-1, 2, NewDecimal(-100, 2))
+
+	type fits struct {
+		Count uint16
+		Rate  int64
+		Rent  int32
+	}
+	var ok []fits
+	if err := tbl.ToStructs(&ok); err != nil {
+		t.Errorf("structs_test: ToStructs: %s\n", err.Error())
+	} else if ok[0].Count != 200 || ok[0].Rate != 1e10 || ok[0].Rent != 251 || ok[1].Rent != -1 {
+		t.Errorf("structs_test: ToStructs: unexpected result %#v\n", ok)
+	}
+
+	type small struct {
+		Count int8
+	}
+	var s []small
+	err := tbl.ToStructs(&s)
+	if err == nil || !strings.Contains(err.Error(), "row 0, field Count: 200 does not fit in a int8") {
+		t.Errorf("structs_test: expected an overflow error for int8, found %v\n", err)
+	}
+	type unsigned struct {
+		Rate uint
+	}
+	var u []unsigned
+	err = tbl.ToStructs(&u)
+	if err == nil || !strings.Contains(err.Error(), "row 1, field Rate:") {
+		t.Errorf("structs_test: expected an error storing -1 in a uint, found %v\n", err)
+	}
+	type single struct {
+		Rate float32
+	}
+	tbl.Putf(0, 1, 1e300)
+	var f []single
+	if err = tbl.ToStructs(&f); err == nil {
+		t.Errorf("structs_test: expected an overflow error for float32\n")
+	}
+
+	type huge struct {
+		Count uint64
+	}
+	if _, err := FromStructs([]huge{{math.MaxUint64}}, nil); err == nil {
+		t.Errorf("structs_test: FromStructs: expected an error for a uint64 that does not fit\n")
+	}
+}
@@ -2,17 +2,16 @@ package gotable
 
 import (
 	"bytes"
-	"fmt"
 	"io"
-	"sort"
-
-	"github.com/dustin/go-humanize"
 )
 
 // TextTable struct used to prepare table in text version
 type TextTable struct {
 	*Table
 	TextColSpace int
+	Options      *TextOptions // pagination, nil for none
+	Plain        bool         // print format rule styles without ANSI escape sequences
+	Border       *TextBorder  // border style, nil for BORDERNONE
 	buf          bytes.Buffer
 }
 
@@ -23,6 +22,13 @@ func (tt *TextTable) writeTableOutput(w io.Writer) error {
 		err error
 	)
 
+	// split into pages if asked to, unless the table has no headers or rows
+	if tt.Options != nil && tt.Options.PageLength > 0 && tt.Table.HasData() == nil {
+		if headerStr, err := tt.formatHeaders(); err == nil {
+			return tt.writePages(w, headerStr)
+		}
+	}
+
 	// append title
 	tt.buf.WriteString(tt.formatTitle())
 
@@ -119,30 +125,22 @@ func (tt *TextTable) formatHeaders() (string, error) {
 
 	var s bytes.Buffer
 
+	// top of the frame
+	s.WriteString(tt.sprintBorderLine(borderTop))
+
+	cells := make([]string, len(tt.Table.ColDefs))
 	for j := 0; j < len(tt.Table.ColDefs[0].Hdr); j++ {
 		for i := 0; i < len(tt.Table.ColDefs); i++ {
-			sf := ""
-			lft := ""
-			if tt.Table.ColDefs[i].Justify == COLJUSTIFYLEFT {
-				lft += "-"
-			}
-			sf += fmt.Sprintf("%%%s%ds", lft, tt.Table.ColDefs[i].Width)
-			s.WriteString(fmt.Sprintf(sf, tt.Table.ColDefs[i].Hdr[j]))
-			s.WriteString(mkstr(tt.TextColSpace, ' '))
+			left := tt.Table.ColDefs[i].Justify == COLJUSTIFYLEFT
+			cells[i] = padToWidth(tt.Table.ColDefs[i].Hdr[j], tt.Table.ColDefs[i].Width, left)
 		}
-
-		// remove last textColSpace from s
-		tmp := s.Bytes()
-		tmp = tmp[0 : len(tmp)-tt.TextColSpace]
-		s.Reset()
-		s.Write(tmp)
-
-		// append new line after first line of grid
-		s.WriteByte('\n')
+		s.WriteString(tt.joinCells(cells))
 	}
 
 	// finally append separator with line
-	s.WriteString(tt.sprintLineText())
+	if tt.Border == nil || !tt.Border.HideHeaderLine {
+		s.WriteString(tt.sprintBorderLine(borderHeader))
+	}
 
 	return s.String(), nil
 }
@@ -162,6 +160,9 @@ func (tt *TextTable) formatRows() (string, error) {
 		rowsOut.WriteString(s)
 	}
 
+	// bottom of the frame
+	rowsOut.WriteString(tt.sprintBorderLine(borderBottom))
+
 	return rowsOut.String(), nil
 }
 
@@ -180,15 +181,8 @@ func (tt *TextTable) formatRow(row int) (string, error) {
 	// format table row
 	var s bytes.Buffer
 
-	if len(tt.Table.LineBefore) > 0 {
-		j := sort.SearchInts(tt.Table.LineBefore, row)
-		// line separator added in `LineAfter`??
-		// If YES, then discard it
-		sepExist := sort.SearchInts(tt.Table.LineAfter, row-1) < tt.Table.RowCount()
-
-		if j < len(tt.Table.LineBefore) && row == tt.Table.LineBefore[j] && !sepExist {
-			s.WriteString(tt.sprintLineText())
-		}
+	if tt.Table.hasLineBefore(row) {
+		s.WriteString(tt.sprintBorderLine(borderTotal))
 	}
 
 	rowColumns := tt.Table.ColCount()
@@ -239,34 +233,33 @@ func (tt *TextTable) formatRow(row int) (string, error) {
 	// for the first line in grid fill all type of data in it
 	// for string type take it from col multi line text map first chunk
 	// FIRST LINE OF ROW GRID
+	cells := make([]string, rowColumns)
 	for gridColIndex := 0; gridColIndex < rowColumns; gridColIndex++ {
 		switch tt.Table.Row[row].Col[gridColIndex].Type {
 		case CELLFLOAT:
-			s.WriteString(fmt.Sprintf(tt.Table.ColDefs[gridColIndex].Pfmt, humanize.FormatFloat("#,###.##", tt.Table.Row[row].Col[gridColIndex].Fval)))
+			cells[gridColIndex] = padToWidth(tt.Table.floatString(gridColIndex, tt.Table.Row[row].Col[gridColIndex].Fval), tt.Table.ColDefs[gridColIndex].Width, false)
 		case CELLINT:
-			s.WriteString(fmt.Sprintf(tt.Table.ColDefs[gridColIndex].Pfmt, tt.Table.Row[row].Col[gridColIndex].Ival))
+			cells[gridColIndex] = tt.Table.sprintInt(gridColIndex, tt.Table.Row[row].Col[gridColIndex].Ival)
+		case CELLDECIMAL:
+			// amounts are never cut, a value wider than its column,
+			// such as a total put with Put, shifts the rest of the line
+			amount := tt.Table.decimalString(gridColIndex, &tt.Table.Row[row].Col[gridColIndex])
+			width := tt.Table.ColDefs[gridColIndex].Width
+			if w := displayWidth(amount); w > width {
+				width = w
+			}
+			cells[gridColIndex] = padToWidth(amount, width, false)
 		case CELLSTRING:
-			s.WriteString(fmt.Sprintf(tt.Table.ColDefs[gridColIndex].Pfmt, colMultiLineTextMap[gridColIndex][0]))
-		case CELLDATE:
-			s.WriteString(fmt.Sprintf("%*.*s", tt.Table.ColDefs[gridColIndex].Width, tt.Table.ColDefs[gridColIndex].Width, tt.Table.Row[row].Col[gridColIndex].Dval.Format(tt.Table.DateFmt)))
-		case CELLDATETIME:
-			s.WriteString(fmt.Sprintf("%*.*s", tt.Table.ColDefs[gridColIndex].Width, tt.Table.ColDefs[gridColIndex].Width, tt.Table.Row[row].Col[gridColIndex].Dval.Format(tt.Table.DateTimeFmt)))
+			cells[gridColIndex] = tt.Table.sprintText(gridColIndex, colMultiLineTextMap[gridColIndex][0])
+		case CELLDATE, CELLDATETIME:
+			cells[gridColIndex] = padToWidth(tt.Table.dateString(gridColIndex, &tt.Table.Row[row].Col[gridColIndex]), tt.Table.ColDefs[gridColIndex].Width, false)
+		case CELLNULL:
+			cells[gridColIndex] = tt.Table.sprintNull(gridColIndex)
 		default:
-			s.WriteString(mkstr(tt.Table.ColDefs[gridColIndex].Width, ' '))
+			cells[gridColIndex] = mkstr(tt.Table.ColDefs[gridColIndex].Width, ' ')
 		}
-
-		// append text col whitespace
-		s.WriteString(mkstr(tt.TextColSpace, ' '))
 	}
-
-	// remove last textColSpace from s
-	tmp := s.Bytes()
-	tmp = tmp[0 : len(tmp)-tt.TextColSpace]
-	s.Reset()
-	s.Write(tmp)
-
-	// append new line after first line of grid
-	s.WriteByte('\n')
+	s.WriteString(tt.joinCells(tt.styleCells(row, cells)))
 
 	// now proceed with rest of the line in row grid
 	// for multi line text
@@ -276,34 +269,20 @@ func (tt *TextTable) formatRow(row int) (string, error) {
 
 			if tt.Table.Row[row].Col[gridColIndex].Type == CELLSTRING {
 				if gridRowIndex >= len(colMultiLineTextMap[gridColIndex]) {
-					rowGrid[gridRowIndex][gridColIndex] = fmt.Sprintf(tt.Table.ColDefs[gridColIndex].Pfmt, "")
+					rowGrid[gridRowIndex][gridColIndex] = tt.Table.sprintText(gridColIndex, "")
 				} else {
-					rowGrid[gridRowIndex][gridColIndex] = fmt.Sprintf(tt.Table.ColDefs[gridColIndex].Pfmt, colMultiLineTextMap[gridColIndex][gridRowIndex])
+					rowGrid[gridRowIndex][gridColIndex] = tt.Table.sprintText(gridColIndex, colMultiLineTextMap[gridColIndex][gridRowIndex])
 				}
 			}
 
 			// write string in the cell
-			s.WriteString(rowGrid[gridRowIndex][gridColIndex])
-
-			// append text col whitespace
-			s.WriteString(mkstr(tt.TextColSpace, ' '))
+			cells[gridColIndex] = rowGrid[gridRowIndex][gridColIndex]
 		}
-
-		// remove last textColSpace from s
-		tmp := s.Bytes()
-		tmp = tmp[0 : len(tmp)-tt.TextColSpace]
-		s.Reset()
-		s.Write(tmp)
-
-		// append new line
-		s.WriteByte('\n')
+		s.WriteString(tt.joinCells(tt.styleCells(row, cells)))
 	}
 
-	if len(tt.Table.LineAfter) > 0 {
-		j := sort.SearchInts(tt.Table.LineAfter, row)
-		if j < len(tt.Table.LineAfter) && row == tt.Table.LineAfter[j] {
-			s.WriteString(tt.sprintLineText())
-		}
+	if tt.Table.hasLineAfter(row) {
+		s.WriteString(tt.sprintBorderLine(borderTotal))
 	}
 	return s.String(), nil
 }
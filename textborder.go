@@ -0,0 +1,144 @@
+package gotable
+
+import (
+	"strings"
+)
+
+// text border styles
+const (
+	BORDERNONE    = 0 // columns separated by spaces, dashes below the headers. The default
+	BORDERASCII   = 1 // +-----+-----+
+	BORDERSINGLE  = 2 // ┌─────┬─────┐
+	BORDERDOUBLE  = 3 // ╔═════╦═════╗
+	BORDERROUNDED = 4 // ╭─────┬─────╮
+)
+
+// TextBorder describes the lines drawn in text output. The lines added with
+// AddLineBefore and AddLineAfter, usually around totals, are drawn with the
+// heavy version of the style. TextColSpace is the space between two columns;
+// with column lines the line is drawn in the middle of that space.
+type TextBorder struct {
+	Style           int  // one of the BORDER constants
+	HideColumnLines bool // no vertical lines between the columns
+	HideFrame       bool // no lines around the table
+	HideHeaderLine  bool // no line below the column headers
+}
+
+// SetTextBorder sets the border style of the text output of the table.
+// Passing nil restores the default, BORDERNONE.
+func (t *Table) SetTextBorder(b *TextBorder) {
+	t.textBorder = b
+}
+
+// kinds of horizontal lines in text output
+const (
+	borderTop    = 0 // top of the frame
+	borderHeader = 1 // below the column headers
+	borderTotal  = 2 // lines added with AddLineBefore and AddLineAfter
+	borderBottom = 3 // bottom of the frame
+)
+
+// borderGlyphs are the characters of a border style. Each line kind has its
+// horizontal character and its left, middle and right junctions.
+type borderGlyphs struct {
+	v     string       // vertical line
+	lines [4][4]string // horizontal, left, junction, right, by kind of line
+}
+
+var borderStyles = map[int]*borderGlyphs{
+	BORDERASCII: {v: "|", lines: [4][4]string{
+		{"-", "+", "+", "+"},
+		{"-", "+", "+", "+"},
+		{"=", "+", "+", "+"},
+		{"-", "+", "+", "+"},
+	}},
+	BORDERSINGLE: {v: "│", lines: [4][4]string{
+		{"─", "┌", "┬", "┐"},
+		{"─", "├", "┼", "┤"},
+		{"━", "┝", "┿", "┥"},
+		{"─", "└", "┴", "┘"},
+	}},
+	BORDERDOUBLE: {v: "║", lines: [4][4]string{
+		{"═", "╔", "╦", "╗"},
+		{"═", "╠", "╬", "╣"},
+		{"═", "╠", "╬", "╣"},
+		{"═", "╚", "╩", "╝"},
+	}},
+	BORDERROUNDED: {v: "│", lines: [4][4]string{
+		{"─", "╭", "┬", "╮"},
+		{"─", "├", "┼", "┤"},
+		{"━", "┝", "┿", "┥"},
+		{"─", "╰", "┴", "╯"},
+	}},
+}
+
+// borderGlyphs returns the characters of the border style, or nil for
+// BORDERNONE
+func (tt *TextTable) borderGlyphs() *borderGlyphs {
+	if tt.Border == nil {
+		return nil
+	}
+	return borderStyles[tt.Border.Style]
+}
+
+// colPadding returns the number of spaces before and after a column line
+func (tt *TextTable) colPadding() (int, int) {
+	after := tt.TextColSpace / 2
+	return tt.TextColSpace - after, after
+}
+
+// joinCells returns one line of text output made of cells, which are already
+// padded to the width of their columns
+func (tt *TextTable) joinCells(cells []string) string {
+	g := tt.borderGlyphs()
+	if g == nil {
+		return strings.Join(cells, mkstr(tt.TextColSpace, ' ')) + "\n"
+	}
+	before, after := tt.colPadding()
+	sep := mkstr(tt.TextColSpace, ' ')
+	if !tt.Border.HideColumnLines {
+		sep = mkstr(before, ' ') + g.v + mkstr(after, ' ')
+	}
+	s := strings.Join(cells, sep)
+	if !tt.Border.HideFrame {
+		s = g.v + mkstr(after, ' ') + s + mkstr(before, ' ') + g.v
+	}
+	return s + "\n"
+}
+
+// sprintBorderLine returns a horizontal line of the supplied kind across the
+// table, or "" if the border style has no such line
+func (tt *TextTable) sprintBorderLine(kind int) string {
+	g := tt.borderGlyphs()
+	if g == nil {
+		if kind == borderTop || kind == borderBottom {
+			return ""
+		}
+		return tt.sprintLineText()
+	}
+	if tt.Border.HideFrame && (kind == borderTop || kind == borderBottom) {
+		return ""
+	}
+
+	h, left, junction, right := g.lines[kind][0], g.lines[kind][1], g.lines[kind][2], g.lines[kind][3]
+	before, after := tt.colPadding()
+	var s strings.Builder
+	if !tt.Border.HideFrame {
+		s.WriteString(left + strings.Repeat(h, after))
+	}
+	for i := 0; i < len(tt.Table.ColDefs); i++ {
+		if i > 0 {
+			if tt.Border.HideColumnLines {
+				s.WriteString(strings.Repeat(h, tt.TextColSpace))
+			} else {
+				s.WriteString(strings.Repeat(h, before) + junction + strings.Repeat(h, after))
+			}
+		}
+		s.WriteString(strings.Repeat(h, tt.Table.ColDefs[i].Width))
+	}
+	if !tt.Border.HideFrame {
+		s.WriteString(strings.Repeat(h, before) + right)
+	}
+	s.WriteString("\n")
+	return s.String()
+}
@@ -0,0 +1,99 @@
+package gotable
+
+import (
+	"testing"
+)
+
+func TestTextBorder(t *testing.T) {
+	var tbl Table
+	tbl.Init()
+	tbl.AddColumn("Unit", 5, CELLSTRING, COLJUSTIFYLEFT)
+	tbl.AddColumn("Rent", 8, CELLFLOAT, COLJUSTIFYRIGHT)
+	tbl.AddRow()
+	tbl.Puts(-1, 0, "101")
+	tbl.Putf(-1, 1, 1250)
+	tbl.AddRow()
+	tbl.Puts(-1, 0, "Total")
+	tbl.Putf(-1, 1, 1250)
+	tbl.AddLineBefore(1)
+
+	tests := []struct {
+		border *TextBorder
+		expect string
+	}{
+		{nil, "" +
+			"Unit       Rent\n" +
+			"-----  --------\n" +
+			"101    1,250.00\n" +
+			"-----  --------\n" +
+			"Total  1,250.00\n"},
+		{&TextBorder{Style: BORDERASCII}, "" +
+			"+-------+----------+\n" +
+			"| Unit  |     Rent |\n" +
+			"+-------+----------+\n" +
+			"| 101   | 1,250.00 |\n" +
+			"+=======+==========+\n" +
+			"| Total | 1,250.00 |\n" +
+			"+-------+----------+\n"},
+		{&TextBorder{Style: BORDERSINGLE}, "" +
+			"┌───────┬──────────┐\n" +
+			"│ Unit  │     Rent │\n" +
+			"├───────┼──────────┤\n" +
+			"│ 101   │ 1,250.00 │\n" +
+			"┝━━━━━━━┿━━━━━━━━━━┥\n" +
+			"│ Total │ 1,250.00 │\n" +
+			"└───────┴──────────┘\n"},
+		{&TextBorder{Style: BORDERDOUBLE, HideHeaderLine: true}, "" +
+			"╔═══════╦══════════╗\n" +
+			"║ Unit  ║     Rent ║\n" +
+			"║ 101   ║ 1,250.00 ║\n" +
+			"╠═══════╬══════════╣\n" +
+			"║ Total ║ 1,250.00 ║\n" +
+			"╚═══════╩══════════╝\n"},
+		{&TextBorder{Style: BORDERROUNDED, HideColumnLines: true}, "" +
+			"╭─────────────────╮\n" +
+			"│ Unit       Rent │\n" +
+			"├─────────────────┤\n" +
+			"│ 101    1,250.00 │\n" +
+			"┝━━━━━━━━━━━━━━━━━┥\n" +
+			"│ Total  1,250.00 │\n" +
+			"╰─────────────────╯\n"},
+		{&TextBorder{Style: BORDERSINGLE, HideFrame: true}, "" +
+			"Unit  │     Rent\n" +
+			"──────┼─────────\n" +
+			"101   │ 1,250.00\n" +
+			"━━━━━━┿━━━━━━━━━\n" +
+			"Total │ 1,250.00\n"},
+	}
+	for i := 0; i < len(tests); i++ {
+		tbl.SetTextBorder(tests[i].border)
+		s, err := tbl.SprintTable()
+		if err != nil {
+			t.Errorf("textborder_test: %d: SprintTable: %s\n", i, err.Error())
+		}
+		if s != tests[i].expect {
+			t.Errorf("textborder_test: %d: expected:\n%s\nfound:\n%s\n", i, tests[i].expect, s)
+		}
+	}
+
+	// the frame is closed on every page
+	tbl.SetTextBorder(&TextBorder{Style: BORDERASCII})
+	tbl.SetTextOptions(&TextOptions{PageLength: 5, RepeatHeaders: true, FormFeed: true})
+	s, _ := tbl.SprintTable()
+	expect := "" +
+		"+-------+----------+\n" +
+		"| Unit  |     Rent |\n" +
+		"+-------+----------+\n" +
+		"| 101   | 1,250.00 |\n" +
+		"+-------+----------+\n" +
+		"\f" +
+		"+-------+----------+\n" +
+		"| Unit  |     Rent |\n" +
+		"+-------+----------+\n" +
+		"+=======+==========+\n" +
+		"| Total | 1,250.00 |\n" +
+		"+-------+----------+\n"
+	if s != expect {
+		t.Errorf("textborder_test: pages: expected:\n%s\nfound:\n%s\n", expect, s)
+	}
+}
@@ -0,0 +1,98 @@
+package gotable
+
+import (
+	"bytes"
+	"io"
+	"strings"
+)
+
+// TextOptions control how text output is split into pages. The page header
+// and footer may use {page} and {pages}, for example "Page {page} of {pages}".
+type TextOptions struct {
+	PageLength    int    // lines per page including the page header and footer, 0 for no pages
+	RepeatHeaders bool   // print the column headers at the top of every page
+	RepeatTitle   bool   // print the title and sections at the top of every page
+	FormFeed      bool   // separate pages with a form feed instead of filling them with blank lines
+	PageHeader    string // line printed at the top of every page
+	PageFooter    string // line printed at the bottom of every page
+}
+
+// SetTextOptions sets the pagination used for the text output of the table.
+// Passing nil turns pagination off.
+func (t *Table) SetTextOptions(opts *TextOptions) {
+	t.textOptions = opts
+}
+
+// lineCount returns the number of lines in s
+func lineCount(s string) int {
+	return strings.Count(s, "\n")
+}
+
+// writePages writes the table to w split into pages. headerStr holds the
+// column headers. A row is never split across two pages, a row that is longer
+// than a page is printed on a page of its own.
+func (tt *TextTable) writePages(w io.Writer, headerStr string) error {
+	o := tt.Options
+	title := tt.formatTitle() + tt.formatSection1() + tt.formatSection2() + tt.formatSection3()
+	bottom := tt.sprintBorderLine(borderBottom)
+
+	// lines available on a page for the table
+	avail := o.PageLength - lineCount(bottom)
+	if o.PageHeader != "" {
+		avail--
+	}
+	if o.PageFooter != "" {
+		avail--
+	}
+
+	var pages []string
+	var page bytes.Buffer
+	lines, rows := 0, 0
+	startPage := func() {
+		page.Reset()
+		if len(pages) == 0 || o.RepeatTitle {
+			page.WriteString(title)
+		}
+		if len(pages) == 0 || o.RepeatHeaders {
+			page.WriteString(headerStr)
+		}
+		lines, rows = lineCount(page.String()), 0
+	}
+
+	startPage()
+	for i := 0; i < tt.Table.RowCount(); i++ {
+		s, _ := tt.formatRow(i)
+		n := lineCount(s)
+		if rows > 0 && lines+n > avail {
+			page.WriteString(bottom)
+			pages = append(pages, page.String())
+			startPage()
+		}
+		page.WriteString(s)
+		lines += n
+		rows++
+	}
+	page.WriteString(bottom)
+	pages = append(pages, page.String())
+
+	for i := 0; i < len(pages); i++ {
+		if i > 0 && o.FormFeed {
+			tt.buf.WriteByte('\f')
+		}
+		if o.PageHeader != "" {
+			tt.buf.WriteString(stringln(pageText(o.PageHeader, i+1, len(pages))))
+		}
+		tt.buf.WriteString(pages[i])
+		if !o.FormFeed && (i < len(pages)-1 || o.PageFooter != "") {
+			for n := lineCount(pages[i]); n < avail+lineCount(bottom); n++ {
+				tt.buf.WriteByte('\n')
+			}
+		}
+		if o.PageFooter != "" {
+			tt.buf.WriteString(stringln(pageText(o.PageFooter, i+1, len(pages))))
+		}
+	}
+
+	_, err := w.Write(tt.buf.Bytes())
+	return err
+}
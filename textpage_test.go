@@ -0,0 +1,71 @@
+package gotable
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTextPages(t *testing.T) {
+	var tbl Table
+	tbl.Init()
+	tbl.SetTitle("Rent Roll")
+	tbl.AddColumn("Unit", 4, CELLSTRING, COLJUSTIFYLEFT)
+	tbl.AddColumn("Notes", 10, CELLSTRING, COLJUSTIFYLEFT)
+	for i := 0; i < 5; i++ {
+		tbl.AddRow()
+		tbl.Puts(-1, 0, "10"+string(rune('1'+i)))
+	}
+	tbl.Puts(2, 1, "one two three") // two lines
+
+	tbl.SetTextOptions(&TextOptions{PageLength: 7, RepeatHeaders: true, PageHeader: "Report", PageFooter: "Page {page} of {pages}"})
+	s, err := tbl.SprintTable()
+	if err != nil {
+		t.Errorf("textpage_test: SprintTable: %s\n", err.Error())
+	}
+	expect := "Report\n" +
+		"Rent Roll\n" +
+		"Unit  Notes     \n" +
+		"----  ----------\n" +
+		"101             \n" +
+		"102             \n" +
+		"Page 1 of 3\n" +
+		"Report\n" +
+		"Unit  Notes     \n" +
+		"----  ----------\n" +
+		"103   one two   \n" +
+		"      three     \n" +
+		"104             \n" +
+		"Page 2 of 3\n" +
+		"Report\n" +
+		"Unit  Notes     \n" +
+		"----  ----------\n" +
+		"105             \n" +
+		"\n" +
+		"\n" +
+		"Page 3 of 3\n"
+	if s != expect {
+		t.Errorf("textpage_test: expected:\n%s\nfound:\n%s\n", expect, s)
+	}
+
+	// form feeds, the title on every page, no padding
+	tbl.SetTextOptions(&TextOptions{PageLength: 5, RepeatTitle: true, FormFeed: true})
+	s, _ = tbl.SprintTable()
+	pages := strings.Split(s, "\f")
+	if len(pages) != 2 {
+		t.Errorf("textpage_test: expected 2 pages, found %d:\n%s\n", len(pages), s)
+	}
+	for i := 0; i < len(pages); i++ {
+		if !strings.HasPrefix(pages[i], "Rent Roll\n") {
+			t.Errorf("textpage_test: page %d does not start with the title:\n%s\n", i+1, pages[i])
+		}
+		if i > 0 && strings.Contains(pages[i], "Unit") {
+			t.Errorf("textpage_test: page %d should not repeat the headers:\n%s\n", i+1, pages[i])
+		}
+	}
+
+	// no pages
+	tbl.SetTextOptions(nil)
+	if s, _ = tbl.SprintTable(); strings.Contains(s, "\f") || strings.Count(s, "Unit") != 1 {
+		t.Errorf("textpage_test: expected output without pages:\n%s\n", s)
+	}
+}
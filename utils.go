@@ -3,6 +3,7 @@ package gotable
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 )
 
@@ -41,10 +42,10 @@ func getMultiLineText(v string, colWidth int) ([]string, int) {
 		sa := strings.Split(standardizeSpaces(textLine), " ")
 		j := 0
 		for i := 0; i < len(sa); i++ { // spin through all substrings
-			if len(sa[i]) <= colWidth && i+1 < len(sa) { // if the width of this substring is less than the requested width, and we're not at the end of the list
+			if displayWidth(sa[i]) <= colWidth && i+1 < len(sa) { // if the width of this substring is less than the requested width, and we're not at the end of the list
 				s := sa[i]                         // we know we're adding this one
 				for k := i + 1; k < len(sa); k++ { // take as many as possible
-					if len(s)+len(sa[k])+1 <= colWidth { // if it fits...
+					if displayWidth(s)+displayWidth(sa[k])+1 <= colWidth { // if it fits...
 						s += " " + sa[k] // ...add it to the list...
 						i = k            // ...and keep loop in sync
 					} else {
@@ -55,8 +56,8 @@ func getMultiLineText(v string, colWidth int) ([]string, int) {
 			} else {
 				a = append(a, sa[i])
 			}
-			if len(a[j]) > maxColWidth { // if there's not enough room for the current string
-				maxColWidth = len(a[j]) // then adjust the max column width we need
+			if displayWidth(a[j]) > maxColWidth { // if there's not enough room for the current string
+				maxColWidth = displayWidth(a[j]) // then adjust the max column width we need
 			}
 			j++
 		}
@@ -87,6 +88,12 @@ func stringln(s string) string {
 	return s
 }
 
+// pageText returns s with the placeholders {page} and {pages} replaced by the
+// page number and the page count
+func pageText(s string, page, pages int) string {
+	return strings.NewReplacer("{page}", strconv.Itoa(page), "{pages}", strconv.Itoa(pages)).Replace(s)
+}
+
 // isValidFilePath checks valid path for file with Mode of path
 // it returns true/false, basename of file/""
 func isValidFilePath(path string) (bool, string) {
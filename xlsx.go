@@ -0,0 +1,565 @@
+package gotable
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// XLSXTable struct used to prepare table in xlsx (Office Open XML workbook) version
+type XLSXTable struct {
+	*Table
+}
+
+func (xt *XLSXTable) writeTableOutput(w io.Writer) error {
+	x := newXLSXWorkbook()
+	x.addTable(xt.Table)
+	return x.write(w)
+}
+
+// xlsxFont is a font in the workbook's style sheet
+type xlsxFont struct {
+	Bold      bool
+	Italic    bool
+	Underline bool
+	Size      int    // points
+	Color     string // RRGGBB, "" for the default
+}
+
+// xlsxStyle is a cell format in the workbook's style sheet
+type xlsxStyle struct {
+	NumFmt int    // number format id
+	Font   int    // index into the fonts
+	Fill   string // background color RRGGBB, "" for none
+	Border int    // 0 none, 1 top line, 2 bottom line, 3 both
+	Align  string // "left", "right" or "" for the default
+}
+
+// xlsxWorkbook collects the sheets and styles of a workbook while it is built
+type xlsxWorkbook struct {
+	sheets     []string // the worksheet xml of each sheet
+	names      []string // the name of each sheet
+	numFmts    []string // custom number formats. Their ids start at 164
+	fonts      []xlsxFont
+	fills      []string // background colors of the custom fills
+	styles     []xlsxStyle
+	styleIndex map[xlsxStyle]int
+}
+
+// xlsxEpoch is day 0 of the Excel date system
+var xlsxEpoch = time.Date(1899, time.December, 30, 0, 0, 0, 0, time.UTC)
+
+func newXLSXWorkbook() *xlsxWorkbook {
+	x := &xlsxWorkbook{styleIndex: map[xlsxStyle]int{}}
+	x.fonts = []xlsxFont{{Size: 11}}
+	x.style(xlsxStyle{}) // the default style must be first
+	return x
+}
+
+// font returns the index of font f, adding it if needed
+func (x *xlsxWorkbook) font(f xlsxFont) int {
+	for i := 0; i < len(x.fonts); i++ {
+		if x.fonts[i] == f {
+			return i
+		}
+	}
+	x.fonts = append(x.fonts, f)
+	return len(x.fonts) - 1
+}
+
+// numFmt returns the id of the number format with the supplied code
+func (x *xlsxWorkbook) numFmt(code string) int {
+	for i := 0; i < len(x.numFmts); i++ {
+		if x.numFmts[i] == code {
+			return 164 + i
+		}
+	}
+	x.numFmts = append(x.numFmts, code)
+	return 164 + len(x.numFmts) - 1
+}
+
+// style returns the index of cell format s, adding it if needed
+func (x *xlsxWorkbook) style(s xlsxStyle) int {
+	if i, ok := x.styleIndex[s]; ok {
+		return i
+	}
+	x.styles = append(x.styles, s)
+	x.styleIndex[s] = len(x.styles) - 1
+	return len(x.styles) - 1
+}
+
+// xlsxEscape returns s escaped for use in xml text or attributes
+func xlsxEscape(s string) string {
+	var b bytes.Buffer
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// xlsxColName returns the column letters of column col, counting from 0
+func xlsxColName(col int) string {
+	s := ""
+	for col++; col > 0; col = (col - 1) / 26 {
+		s = string(rune('A'+(col-1)%26)) + s
+	}
+	return s
+}
+
+// xlsxDateFmt returns the Excel number format for the go time layout layout
+func xlsxDateFmt(layout string) string {
+	tokens := []struct{ goFmt, xlFmt string }{
+		{"January", "mmmm"}, {"Monday", "dddd"}, {"Z07:00", ""}, {"-07:00", ""}, {"-0700", ""},
+		{"2006", "yyyy"}, {"Jan", "mmm"}, {"Mon", "ddd"}, {"MST", ""},
+		{"15", "hh"}, {"01", "mm"}, {"02", "dd"}, {"_2", "d"}, {"03", "hh"}, {"04", "mm"}, {"05", "ss"},
+		{"06", "yy"}, {"PM", "AM/PM"}, {"pm", "AM/PM"},
+		{"1", "m"}, {"2", "d"}, {"3", "h"}, {"4", "m"}, {"5", "s"},
+	}
+	var b strings.Builder
+	for len(layout) > 0 {
+		found := false
+		for i := 0; i < len(tokens); i++ {
+			if strings.HasPrefix(layout, tokens[i].goFmt) {
+				b.WriteString(tokens[i].xlFmt)
+				layout = layout[len(tokens[i].goFmt):]
+				found = true
+				break
+			}
+		}
+		if found {
+			continue
+		}
+		if strings.IndexByte(" /-:.,", layout[0]) < 0 {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(layout[0])
+		layout = layout[1:]
+	}
+	s := strings.TrimSpace(b.String())
+	if s == "" {
+		return "yyyy-mm-dd"
+	}
+	return s
+}
+
+// xlsxSheetName returns a valid sheet name made from title that is not in used
+func xlsxSheetName(title string, n int, used []string) string {
+	name := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) || r < ' ' {
+			return ' '
+		}
+		return r
+	}, title)
+	name = strings.Join(strings.Fields(name), " ")
+	if name == "" {
+		name = "Sheet" + strconv.Itoa(n)
+	}
+	base := name
+	for i := 2; ; i++ {
+		if len([]rune(name)) > 31 {
+			name = string([]rune(name)[:31])
+		}
+		dup := false
+		for k := 0; k < len(used); k++ {
+			if strings.EqualFold(used[k], name) {
+				dup = true
+			}
+		}
+		if !dup {
+			return name
+		}
+		suffix := " (" + strconv.Itoa(i) + ")"
+		r := []rune(base)
+		if len(r)+len(suffix) > 31 {
+			r = r[:31-len(suffix)]
+		}
+		name = string(r) + suffix
+	}
+}
+
+// xlsxNumberFmt returns the number format code for nf. Excel shows the
+// grouping and decimal separators of the reader's locale, so only the use of
+// grouping, the negative style and the zero text are carried over.
+func xlsxNumberFmt(nf *NumberFormat, decimals int, symbol string) string {
+	code := "0"
+	if nf.Grouping != "" {
+		code = "#,##0"
+	}
+	if decimals > 0 {
+		code += "." + strings.Repeat("0", decimals)
+	}
+	if symbol != "" {
+		code = xlsxQuote(symbol) + code
+	}
+	neg := ""
+	switch nf.Negative {
+	case NEGPARENS:
+		neg = ";(" + code + ")"
+	case NEGCR:
+		neg = ";" + code + `" CR"`
+	}
+	if nf.ZeroText != "" {
+		if neg == "" {
+			neg = ";-" + code
+		}
+		return code + neg + ";" + xlsxQuote(nf.ZeroText)
+	}
+	return code + neg
+}
+
+// xlsxQuote returns s as a literal in a number format code
+func xlsxQuote(s string) string {
+	return `"` + strings.Replace(s, `"`, `""`, -1) + `"`
+}
+
+// cellStyle returns the cell format for a data cell of column col
+func (x *xlsxWorkbook) cellStyle(t *Table, col int, c *Cell) xlsxStyle {
+	cd := &t.ColDefs[col]
+	var s xlsxStyle
+	switch c.Type {
+	case CELLINT:
+		s.NumFmt = 1 // 0
+		if cd.NumFmt != nil {
+			s.NumFmt = x.numFmt(xlsxNumberFmt(cd.NumFmt, 0, ""))
+		}
+	case CELLFLOAT:
+		s.NumFmt = x.numFmt(xlsxNumberFmt(t.numberFormat(col), cd.Fdecimals, ""))
+	case CELLDECIMAL:
+		s.NumFmt = x.numFmt(xlsxNumberFmt(t.numberFormat(col), c.Mval.Scale, cd.Currency))
+	case CELLDATE, CELLDATETIME:
+		layout := t.dateLayout(col, c)
+		if layout == DATERELATIVE { // a spreadsheet keeps the date, not the text
+			layout = t.DateFmt
+			if c.Type == CELLDATETIME {
+				layout = t.DateTimeFmt
+			}
+		}
+		s.NumFmt = x.numFmt(xlsxDateFmt(layout))
+	}
+	if cd.Justify == COLJUSTIFYRIGHT {
+		s.Align = "right"
+	} else {
+		s.Align = "left"
+	}
+	return s
+}
+
+// xlsxSerial returns the Excel serial number of the date d, using its wall clock time
+func xlsxSerial(d time.Time) float64 {
+	y, m, day := d.Date()
+	u := time.Date(y, m, day, d.Hour(), d.Minute(), d.Second(), d.Nanosecond(), time.UTC)
+	return u.Sub(xlsxEpoch).Hours() / 24
+}
+
+// xlsxRow accumulates the cells of one row of a worksheet
+type xlsxRow struct {
+	r int
+	b bytes.Buffer
+}
+
+func (xr *xlsxRow) str(col, style int, s string) {
+	fmt.Fprintf(&xr.b, `<c r="%s%d" s="%d" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`,
+		xlsxColName(col), xr.r, style, xlsxEscape(s))
+}
+
+func (xr *xlsxRow) num(col, style int, v string) {
+	fmt.Fprintf(&xr.b, `<c r="%s%d" s="%d"><v>%s</v></c>`, xlsxColName(col), xr.r, style, v)
+}
+
+func (xr *xlsxRow) blank(col, style int) {
+	fmt.Fprintf(&xr.b, `<c r="%s%d" s="%d"/>`, xlsxColName(col), xr.r, style)
+}
+
+// addTable adds a worksheet holding table t to the workbook
+func (x *xlsxWorkbook) addTable(t *Table) {
+	var sd bytes.Buffer // sheetData
+	var merges []string
+	ncols := len(t.ColDefs)
+	if ncols == 0 {
+		ncols = 1
+	}
+	r := 0
+	writeRow := func(xr *xlsxRow) {
+		fmt.Fprintf(&sd, `<row r="%d">%s</row>`, xr.r, xr.b.String())
+	}
+
+	// title and sections, each merged across the width of the table
+	titles := []struct {
+		s    string
+		font xlsxFont
+	}{
+		{t.Title, xlsxFont{Bold: true, Size: 14}},
+		{t.Section1, xlsxFont{Bold: true, Size: 11}},
+		{t.Section2, xlsxFont{Size: 11}},
+		{t.Section3, xlsxFont{Size: 11}},
+	}
+	for i := 0; i < len(titles); i++ {
+		s := strings.TrimSpace(titles[i].s)
+		if s == "" {
+			continue
+		}
+		r++
+		xr := xlsxRow{r: r}
+		xr.str(0, x.style(xlsxStyle{Font: x.font(titles[i].font), Align: "left"}), s)
+		writeRow(&xr)
+		if ncols > 1 {
+			merges = append(merges, fmt.Sprintf("A%d:%s%d", r, xlsxColName(ncols-1), r))
+		}
+	}
+
+	// column headers
+	hdrRow := 0
+	if len(t.ColDefs) > 0 {
+		r++
+		hdrRow = r
+		xr := xlsxRow{r: r}
+		for col := 0; col < len(t.ColDefs); col++ {
+			s := xlsxStyle{Font: x.font(xlsxFont{Bold: true, Size: 11}), Border: 2, Align: "left"}
+			if t.ColDefs[col].Justify == COLJUSTIFYRIGHT {
+				s.Align = "right"
+			}
+			xr.str(col, x.style(s), t.ColDefs[col].ColTitle)
+		}
+		writeRow(&xr)
+	}
+
+	// data
+	for row := 0; row < len(t.Row); row++ {
+		r++
+		xr := xlsxRow{r: r}
+		border := 0
+		if t.hasLineBefore(row) {
+			border |= 1
+		}
+		if t.hasLineAfter(row) {
+			border |= 2
+		}
+		for col := 0; col < len(t.Row[row].Col); col++ {
+			c := &t.Row[row].Col[col]
+			s := x.cellStyle(t, col, c)
+			s.Border = border
+			if st := t.ruleStyle(row, col); st != nil {
+				s.Font = x.font(xlsxFont{Bold: st.Bold, Italic: st.Italic, Underline: st.Underline, Size: 11, Color: xlsxColor(st.Color)})
+				s.Fill = xlsxColor(st.Background)
+			}
+			style := x.style(s)
+			switch c.Type {
+			case CELLINT:
+				xr.num(col, style, strconv.FormatInt(c.Ival, 10))
+			case CELLFLOAT:
+				if math.IsNaN(c.Fval) || math.IsInf(c.Fval, 0) {
+					xr.str(col, style, strconv.FormatFloat(c.Fval, 'g', -1, 64))
+				} else {
+					xr.num(col, style, strconv.FormatFloat(c.Fval, 'g', -1, 64))
+				}
+			case CELLDECIMAL:
+				xr.num(col, style, c.Mval.String())
+			case CELLDATE, CELLDATETIME:
+				xr.num(col, style, strconv.FormatFloat(xlsxSerial(t.dateValue(col, c)), 'f', -1, 64))
+			case CELLSTRING:
+				xr.str(col, style, c.Sval)
+			case CELLNULL:
+				if s := t.colNullText(col); s != "" {
+					xr.str(col, style, s)
+				} else if border != 0 {
+					xr.blank(col, style)
+				}
+			default:
+				if border != 0 {
+					xr.blank(col, style)
+				}
+			}
+		}
+		writeRow(&xr)
+	}
+
+	var b bytes.Buffer
+	b.WriteString(xml.Header)
+	b.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	if hdrRow > 0 {
+		fmt.Fprintf(&b, `<sheetViews><sheetView workbookViewId="0"><pane ySplit="%d" topLeftCell="A%d" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>`, hdrRow, hdrRow+1)
+	}
+	if len(t.ColDefs) > 0 {
+		b.WriteString(`<cols>`)
+		for col := 0; col < len(t.ColDefs); col++ {
+			fmt.Fprintf(&b, `<col min="%d" max="%d" width="%d" customWidth="1"/>`, col+1, col+1, t.ColDefs[col].Width+2)
+		}
+		b.WriteString(`</cols>`)
+	}
+	b.WriteString(`<sheetData>`)
+	b.Write(sd.Bytes())
+	b.WriteString(`</sheetData>`)
+	if len(merges) > 0 {
+		fmt.Fprintf(&b, `<mergeCells count="%d">`, len(merges))
+		for i := 0; i < len(merges); i++ {
+			fmt.Fprintf(&b, `<mergeCell ref="%s"/>`, merges[i])
+		}
+		b.WriteString(`</mergeCells>`)
+	}
+	b.WriteString(`</worksheet>`)
+
+	x.names = append(x.names, xlsxSheetName(t.Title, len(x.sheets)+1, x.names))
+	x.sheets = append(x.sheets, b.String())
+}
+
+// stylesXML returns the style sheet of the workbook
+func (x *xlsxWorkbook) stylesXML() string {
+	var b bytes.Buffer
+	b.WriteString(xml.Header)
+	b.WriteString(`<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	if len(x.numFmts) > 0 {
+		fmt.Fprintf(&b, `<numFmts count="%d">`, len(x.numFmts))
+		for i := 0; i < len(x.numFmts); i++ {
+			fmt.Fprintf(&b, `<numFmt numFmtId="%d" formatCode="%s"/>`, 164+i, xlsxEscape(x.numFmts[i]))
+		}
+		b.WriteString(`</numFmts>`)
+	}
+	fmt.Fprintf(&b, `<fonts count="%d">`, len(x.fonts))
+	for i := 0; i < len(x.fonts); i++ {
+		b.WriteString(`<font>`)
+		if x.fonts[i].Bold {
+			b.WriteString(`<b/>`)
+		}
+		if x.fonts[i].Italic {
+			b.WriteString(`<i/>`)
+		}
+		if x.fonts[i].Underline {
+			b.WriteString(`<u/>`)
+		}
+		fmt.Fprintf(&b, `<sz val="%d"/>`, x.fonts[i].Size)
+		if x.fonts[i].Color != "" {
+			fmt.Fprintf(&b, `<color rgb="FF%s"/>`, x.fonts[i].Color)
+		}
+		b.WriteString(`<name val="Calibri"/><family val="2"/></font>`)
+	}
+	b.WriteString(`</fonts>`)
+
+	// fills 0 and 1 are required by Excel
+	fills := []string{}
+	fillIndex := map[string]int{}
+	for i := 0; i < len(x.styles); i++ {
+		if c := x.styles[i].Fill; c != "" {
+			if _, ok := fillIndex[c]; !ok {
+				fillIndex[c] = len(fills) + 2
+				fills = append(fills, c)
+			}
+		}
+	}
+	fmt.Fprintf(&b, `<fills count="%d"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill>`, len(fills)+2)
+	for i := 0; i < len(fills); i++ {
+		fmt.Fprintf(&b, `<fill><patternFill patternType="solid"><fgColor rgb="FF%s"/><bgColor indexed="64"/></patternFill></fill>`, fills[i])
+	}
+	b.WriteString(`</fills>`)
+
+	b.WriteString(`<borders count="4">`)
+	for i := 0; i < 4; i++ {
+		b.WriteString(`<border><left/><right/>`)
+		if i&1 != 0 {
+			b.WriteString(`<top style="thin"><color auto="1"/></top>`)
+		} else {
+			b.WriteString(`<top/>`)
+		}
+		if i&2 != 0 {
+			b.WriteString(`<bottom style="thin"><color auto="1"/></bottom>`)
+		} else {
+			b.WriteString(`<bottom/>`)
+		}
+		b.WriteString(`<diagonal/></border>`)
+	}
+	b.WriteString(`</borders>`)
+
+	b.WriteString(`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>`)
+	fmt.Fprintf(&b, `<cellXfs count="%d">`, len(x.styles))
+	for i := 0; i < len(x.styles); i++ {
+		s := &x.styles[i]
+		fill := 0
+		if s.Fill != "" {
+			fill = fillIndex[s.Fill]
+		}
+		fmt.Fprintf(&b, `<xf numFmtId="%d" fontId="%d" fillId="%d" borderId="%d" xfId="0"`, s.NumFmt, s.Font, fill, s.Border)
+		if i > 0 {
+			b.WriteString(` applyNumberFormat="1" applyFont="1" applyFill="1" applyBorder="1"`)
+		}
+		if s.Align != "" {
+			fmt.Fprintf(&b, ` applyAlignment="1"><alignment horizontal="%s"/></xf>`, s.Align)
+		} else {
+			b.WriteString(`/>`)
+		}
+	}
+	b.WriteString(`</cellXfs>`)
+	b.WriteString(`<cellStyles count="1"><cellStyle name="Normal" xfId="0" builtinId="0"/></cellStyles>`)
+	b.WriteString(`</styleSheet>`)
+	return b.String()
+}
+
+// write writes the workbook to w as an xlsx file
+func (x *xlsxWorkbook) write(w io.Writer) error {
+	const ns = `http://schemas.openxmlformats.org/`
+	files := []struct{ name, body string }{}
+	add := func(name, body string) {
+		files = append(files, struct{ name, body string }{name, body})
+	}
+
+	var ct bytes.Buffer
+	ct.WriteString(xml.Header)
+	ct.WriteString(`<Types xmlns="` + ns + `package/2006/content-types">`)
+	ct.WriteString(`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>`)
+	ct.WriteString(`<Default Extension="xml" ContentType="application/xml"/>`)
+	ct.WriteString(`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>`)
+	ct.WriteString(`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`)
+	for i := 0; i < len(x.sheets); i++ {
+		fmt.Fprintf(&ct, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i+1)
+	}
+	ct.WriteString(`</Types>`)
+	add("[Content_Types].xml", ct.String())
+
+	add("_rels/.rels", xml.Header+`<Relationships xmlns="`+ns+`package/2006/relationships">`+
+		`<Relationship Id="rId1" Type="`+ns+`officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>`+
+		`</Relationships>`)
+
+	var wb, rels bytes.Buffer
+	wb.WriteString(xml.Header)
+	wb.WriteString(`<workbook xmlns="` + ns + `spreadsheetml/2006/main" xmlns:r="` + ns + `officeDocument/2006/relationships"><sheets>`)
+	rels.WriteString(xml.Header)
+	rels.WriteString(`<Relationships xmlns="` + ns + `package/2006/relationships">`)
+	for i := 0; i < len(x.sheets); i++ {
+		fmt.Fprintf(&wb, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, xlsxEscape(x.names[i]), i+1, i+1)
+		fmt.Fprintf(&rels, `<Relationship Id="rId%d" Type="%sofficeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, i+1, ns, i+1)
+	}
+	wb.WriteString(`</sheets></workbook>`)
+	fmt.Fprintf(&rels, `<Relationship Id="rId%d" Type="%sofficeDocument/2006/relationships/styles" Target="styles.xml"/>`, len(x.sheets)+1, ns)
+	rels.WriteString(`</Relationships>`)
+	add("xl/workbook.xml", wb.String())
+	add("xl/_rels/workbook.xml.rels", rels.String())
+	add("xl/styles.xml", x.stylesXML())
+	for i := 0; i < len(x.sheets); i++ {
+		add(fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1), x.sheets[i])
+	}
+
+	zw := zip.NewWriter(w)
+	for i := 0; i < len(files); i++ {
+		f, err := zw.Create(files[i].name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, files[i].body); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// MultiTableXLSXPrint writes the tables in m to w as an xlsx workbook with one
+// sheet per table. Sheets are named after the table titles.
+func MultiTableXLSXPrint(m []Table, w io.Writer) error {
+	x := newXLSXWorkbook()
+	for i := 0; i < len(m); i++ {
+		m[i].Recompute()
+		x.addTable(&m[i])
+	}
+	return x.write(w)
+}
@@ -0,0 +1,125 @@
+package gotable
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+)
+
+// xlsxFiles returns the contents of the files in the xlsx file b
+func xlsxFiles(t *testing.T, b []byte) map[string]string {
+	zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		t.Errorf("xlsx_test: output is not a zip file: %s\n", err.Error())
+		return nil
+	}
+	m := map[string]string{}
+	for i := 0; i < len(zr.File); i++ {
+		f, err := zr.File[i].Open()
+		if err != nil {
+			t.Errorf("xlsx_test: %s: %s\n", zr.File[i].Name, err.Error())
+			continue
+		}
+		data, _ := ioutil.ReadAll(f)
+		f.Close()
+		m[zr.File[i].Name] = string(data)
+
+		// every part must be well formed xml
+		d := xml.NewDecoder(bytes.NewReader(data))
+		for {
+			_, err := d.Token()
+			if err != nil {
+				if err != io.EOF {
+					t.Errorf("xlsx_test: %s is not valid xml: %s\n", zr.File[i].Name, err.Error())
+				}
+				break
+			}
+		}
+	}
+	return m
+}
+
+func TestXLSX(t *testing.T) {
+	var tbl Table
+	tbl.Init()
+	tbl.SetTitle("Rent Roll")
+	tbl.SetSection1("Building A & B")
+	tbl.AddColumn("Unit", 6, CELLSTRING, COLJUSTIFYLEFT)
+	tbl.AddColumn("Beds", 4, CELLINT, COLJUSTIFYRIGHT)
+	tbl.AddColumn("Rent", 10, CELLFLOAT, COLJUSTIFYRIGHT)
+	tbl.AddColumn("Deposit", 10, CELLDECIMAL, COLJUSTIFYRIGHT)
+	tbl.AddColumn("Move In", 10, CELLDATE, COLJUSTIFYLEFT)
+	tbl.SetColDecimal(3, 2, "$", ROUNDHALFUP)
+	tbl.SetNullText("n/a")
+
+	tbl.AddRow()
+	tbl.Puts(-1, 0, "<101>")
+	tbl.Puti(-1, 1, 2)
+	tbl.Putf(-1, 2, 1234.5)
+	tbl.Putm(-1, 3, NewDecimal(50000, 2))
+	tbl.Putd(-1, 4, time.Date(2017, time.June, 1, 0, 0, 0, 0, time.UTC))
+	tbl.AddRow()
+	tbl.Puts(-1, 0, "102")
+	tbl.PutNull(-1, 3)
+	tbl.AddLineAfter(1)
+
+	var b bytes.Buffer
+	if err := tbl.XLSXprintTable(&b); err != nil {
+		t.Errorf("xlsx_test: XLSXprintTable: %s\n", err.Error())
+		return
+	}
+	m := xlsxFiles(t, b.Bytes())
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/styles.xml", "xl/worksheets/sheet1.xml"} {
+		if _, ok := m[name]; !ok {
+			t.Errorf("xlsx_test: missing %s\n", name)
+		}
+	}
+	sheet := m["xl/worksheets/sheet1.xml"]
+	for _, s := range []string{
+		`<mergeCell ref="A1:E1"/>`, `<mergeCell ref="A2:E2"/>`,
+		`Building A &amp; B`, `&lt;101&gt;`,
+		`<c r="B4" s="`, `"><v>2</v></c>`,
+		`"><v>1234.5</v></c>`,
+		`"><v>500.00</v></c>`,
+		`"><v>42887</v></c>`,
+		`<t xml:space="preserve">n/a</t>`,
+		`<col min="3" max="3" width="12" customWidth="1"/>`,
+		`<pane ySplit="3" topLeftCell="A4"`,
+	} {
+		if !strings.Contains(sheet, s) {
+			t.Errorf("xlsx_test: sheet does not contain %s:\n%s\n", s, sheet)
+		}
+	}
+	styles := m["xl/styles.xml"]
+	for _, s := range []string{`formatCode="#,##0.00"`, `formatCode="&#34;$&#34;#,##0.00"`, `formatCode="mm/dd/yyyy"`} {
+		if !strings.Contains(styles, s) {
+			t.Errorf("xlsx_test: styles do not contain %s:\n%s\n", s, styles)
+		}
+	}
+
+	// one sheet per table, with unique names
+	b.Reset()
+	if err := MultiTableXLSXPrint([]Table{tbl, tbl}, &b); err != nil {
+		t.Errorf("xlsx_test: MultiTableXLSXPrint: %s\n", err.Error())
+		return
+	}
+	m = xlsxFiles(t, b.Bytes())
+	if _, ok := m["xl/worksheets/sheet2.xml"]; !ok {
+		t.Errorf("xlsx_test: expected two sheets\n")
+	}
+	if wb := m["xl/workbook.xml"]; !strings.Contains(wb, `name="Rent Roll"`) || !strings.Contains(wb, `name="Rent Roll (2)"`) {
+		t.Errorf("xlsx_test: unexpected sheet names:\n%s\n", wb)
+	}
+
+	if s := xlsxColName(27); s != "AB" {
+		t.Errorf("xlsx_test: xlsxColName(27): expected AB, found %s\n", s)
+	}
+	if s := xlsxDateFmt("Jan 2, 2006 15:04:05"); s != "mmm d, yyyy hh:mm:ss" {
+		t.Errorf("xlsx_test: xlsxDateFmt: found %s\n", s)
+	}
+}